@@ -2,14 +2,20 @@
 package cache
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 )
 
+// ErrTooLarge is reported by [Cache.PutErr] if a value is too large to be
+// stored in the cache, even if the cache were empty.
+var ErrTooLarge = errors.New("value too large for cache")
+
 // A Cache is a cache mapping keys to values, with a fixed limit on its maximum
 // capacity. Any key may be present in the cache at most once. By default,
 // cache capacity is a number of elements; however, the caller may specify a
-// different size metric using the [Config] argument to [New].
+// different size metric using the [Config] argument to [New]. When a size
+// metric is used, the number of elements may also be limited separately.
 //
 // A Cache is safe for concurrent access by multiple goroutines.
 type Cache[Key comparable, Value any] struct {
@@ -18,10 +24,17 @@ type Cache[Key comparable, Value any] struct {
 	store       Store[Key, Value]
 	size, limit int64
 	count       int
+	maxCount    int // if positive, the maximum number of entries
+
+	// If deferEvict is set, evicted entries are queued in pending while μ is
+	// held, and dispatched to onEvict by whichever goroutine sets dispatching.
+	pending     []entry[Key, Value]
+	dispatching bool
 
 	// Set once at construction, read-only thereafter.
-	sizeOf  func(Value) int64
-	onEvict func(Key, Value)
+	sizeOf     func(Value) int64
+	onEvict    func(Key, Value)
+	deferEvict bool
 
 	// TODO(creachadair): add metrics
 }
@@ -48,28 +61,44 @@ func (c *Cache[K, V]) Get(key K) (V, bool) {
 // to store the provided value; otherwise, the cache is updated and Put reports
 // true. If necessary, items are evicted from the cache to make room for the
 // new value. Which values are evicted is determined by the cache store.
-func (c *Cache[K, V]) Put(key K, val V) bool {
+func (c *Cache[K, V]) Put(key K, val V) bool { return c.PutErr(key, val) == nil }
+
+// PutErr adds or replaces the value for key in c, and reports an error if the
+// value could not be stored. It behaves like [Cache.Put], but reports the
+// reason for a failure. If the value is larger than the capacity of the cache,
+// the error wraps [ErrTooLarge].
+func (c *Cache[K, V]) PutErr(key K, val V) error {
+	defer c.dispatch()
+	return c.putErr(key, val)
+}
+
+// putErr implements [Cache.PutErr], but does not dispatch deferred eviction
+// callbacks. The caller must call c.dispatch after releasing any locks that
+// the callbacks may need.
+func (c *Cache[K, V]) putErr(key K, val V) error {
 	c.μ.Lock()
 	defer c.μ.Unlock()
 
 	valSize := c.sizeOf(val)
 	if valSize > c.limit {
-		return false // this value will never fit
+		// This value will never fit.
+		return fmt.Errorf("size %d exceeds limit %d: %w", valSize, c.limit, ErrTooLarge)
 	}
 
 	// If there is an existing item for this key, remove it.
 	if old, ok := c.store.Check(key); ok {
 		c.store.Remove(key)
-		c.onEvict(key, old)
+		c.evicted(key, old)
 		c.size -= c.sizeOf(old)
 		c.count--
 	}
 
-	// If necessary, evict items to make room.
+	// If necessary, evict items to make room. If there is a limit on the
+	// number of entries, that must also make room.
 	newSize := c.size + valSize
-	for newSize > c.limit {
+	for newSize > c.limit || (c.maxCount > 0 && c.count >= c.maxCount) {
 		ek, ev := c.store.Evict()
-		c.onEvict(ek, ev)
+		c.evicted(ek, ev)
 		c.count--
 		newSize -= c.sizeOf(ev)
 	}
@@ -78,18 +107,25 @@ func (c *Cache[K, V]) Put(key K, val V) bool {
 	c.store.Store(key, val)
 	c.size = newSize
 	c.count++
-	return true
+	return nil
 }
 
 // Remove removes the specified key from c, and reports whether a value had
 // been cached for that key.
 func (c *Cache[K, _]) Remove(key K) bool {
+	defer c.dispatch()
+	return c.remove(key)
+}
+
+// remove implements [Cache.Remove], but does not dispatch deferred eviction
+// callbacks, as for putErr.
+func (c *Cache[K, _]) remove(key K) bool {
 	c.μ.Lock()
 	defer c.μ.Unlock()
 
 	if old, ok := c.store.Check(key); ok {
 		c.store.Remove(key)
-		c.onEvict(key, old)
+		c.evicted(key, old)
 		c.size -= c.sizeOf(old)
 		c.count--
 		return true
@@ -106,12 +142,19 @@ func (c *Cache[K, V]) Len() int {
 
 // Clear discards the complete contents of c, leaving it empty.
 func (c *Cache[K, V]) Clear() {
+	defer c.dispatch()
+	c.clear()
+}
+
+// clear implements [Cache.Clear], but does not dispatch deferred eviction
+// callbacks, as for putErr.
+func (c *Cache[K, V]) clear() {
 	c.μ.Lock()
 	defer c.μ.Unlock()
 
 	for c.count > 0 {
 		ek, ev := c.store.Evict()
-		c.onEvict(ek, ev)
+		c.evicted(ek, ev)
 		c.size -= c.sizeOf(ev)
 		c.count--
 	}
@@ -120,6 +163,53 @@ func (c *Cache[K, V]) Clear() {
 	}
 }
 
+// An entry is a key-value pair awaiting dispatch to an eviction callback.
+type entry[Key comparable, Value any] struct {
+	key Key
+	val Value
+}
+
+// evicted records the eviction of key and val from c. If eviction callbacks
+// are deferred, the entry is queued for dispatch; otherwise the callback is
+// invoked immediately. The caller must hold c.μ.
+func (c *Cache[K, V]) evicted(key K, val V) {
+	if c.deferEvict {
+		c.pending = append(c.pending, entry[K, V]{key: key, val: val})
+	} else {
+		c.onEvict(key, val)
+	}
+}
+
+// dispatch delivers any pending evicted entries to the eviction callback, in
+// the order they were evicted. The caller must not hold c.μ.
+//
+// At most one goroutine dispatches at a time. If another goroutine is already
+// dispatching, dispatch returns at once, and the entries queued by the caller
+// are delivered by the other goroutine before it finishes.
+func (c *Cache[K, V]) dispatch() {
+	if !c.deferEvict {
+		return
+	}
+	c.μ.Lock()
+	if c.dispatching {
+		c.μ.Unlock()
+		return
+	}
+	for len(c.pending) != 0 {
+		batch := c.pending
+		c.pending = nil
+		c.dispatching = true
+		c.μ.Unlock()
+
+		for _, e := range batch {
+			c.onEvict(e.key, e.val)
+		}
+		c.μ.Lock()
+	}
+	c.dispatching = false
+	c.μ.Unlock()
+}
+
 // Size reports the current size of the items in c.
 func (c *Cache[K, V]) Size() int64 {
 	c.μ.Lock()
@@ -133,14 +223,19 @@ func New[K comparable, V any](config Config[K, V]) *Cache[K, V] {
 	if config.limit <= 0 {
 		panic("cache: limit must be positive")
 	}
+	if config.maxCount < 0 {
+		panic("cache: entry limit must be non-negative")
+	}
 	if config.store == nil {
 		panic("cache: no store implementation")
 	}
 	return &Cache[K, V]{
-		store:   config.store,
-		limit:   config.limit,
-		sizeOf:  config.sizeFunc(),
-		onEvict: config.onEvictFunc(),
+		store:      config.store,
+		limit:      config.limit,
+		maxCount:   config.maxCount,
+		sizeOf:     config.sizeFunc(),
+		onEvict:    config.onEvictFunc(),
+		deferEvict: config.deferEvict && config.onEvict != nil,
 	}
 }
 
@@ -150,7 +245,9 @@ func New[K comparable, V any](config Config[K, V]) *Cache[K, V] {
 //   - Use [Config.WithLimit] to set the capacity.
 //   - Use [Config.WithStore] to set the storage implementation.
 //   - Use [Config.WithSize] to set the size function.
+//   - Use [Config.WithMaxEntries] to limit the number of entries.
 //   - Use [Config.OnEvict] to set the eviction callback.
+//   - Use [Config.WithDeferredEvict] to run eviction callbacks after unlocking.
 //
 // A zero Config is invalid; at least the store field must be set.
 type Config[Key comparable, Value any] struct {
@@ -166,8 +263,16 @@ type Config[Key comparable, Value any] struct {
 	// size is 1, meaning the limit is a number of cache entries.
 	sizeOf func(v Value) int64
 
+	// maxCount, if positive, is the maximum number of entries in the cache,
+	// regardless of their total size.
+	maxCount int
+
 	// onEvict, if non-nil, is called for each entry evicted from the cache.
 	onEvict func(key Key, val Value)
+
+	// deferEvict, if true, means onEvict is called after the cache lock is
+	// released rather than while it is held.
+	deferEvict bool
 }
 
 // WithLimit returns a copy of c with its capacity set to n.
@@ -184,12 +289,35 @@ func (c Config[K, V]) WithStore(s Store[K, V]) Config[K, V] { c.store = s; retur
 // limit is based on the number of entries in the cache.
 func (c Config[K, V]) WithSize(sizeOf func(V) int64) Config[K, V] { c.sizeOf = sizeOf; return c }
 
+// WithMaxEntries returns a copy of c with its limit on the number of entries
+// set to n. If n == 0, the number of entries is not limited apart from the
+// capacity limit. If n < 0, [New] will panic.
+//
+// The entry limit applies in addition to the capacity limit, and entries are
+// evicted when storing a new value would exceed either limit. This is useful
+// in combination with [Config.WithSize], to bound both the total size of the
+// entries and how many there are.
+func (c Config[K, V]) WithMaxEntries(n int) Config[K, V] { c.maxCount = n; return c }
+
 // OnEvict returns a copy of c with its eviction callback set to f.
 //
 // If an eviction callback is set, it is called for each entry removed or
 // evicted from the cache.
 func (c Config[K, V]) OnEvict(f func(K, V)) Config[K, V] { c.onEvict = f; return c }
 
+// WithDeferredEvict returns a copy of c with deferred eviction callbacks
+// enabled (ok == true) or disabled (ok == false).
+//
+// By default, the eviction callback is called while the cache lock is held,
+// so a slow callback stalls all other use of the cache, and the callback must
+// not call methods of the cache. With deferred eviction, evicted entries are
+// queued while the lock is held, and delivered to the callback in batches
+// after it is released. Callbacks are still delivered one at a time, in the
+// order the entries were evicted, but may run on a different goroutine than
+// the one whose operation evicted them, possibly after that operation has
+// returned. A deferred callback may safely call methods of the cache.
+func (c Config[K, V]) WithDeferredEvict(ok bool) Config[K, V] { c.deferEvict = ok; return c }
+
 func (c Config[K, V]) sizeFunc() func(V) int64 {
 	if c.sizeOf != nil {
 		return c.sizeOf
@@ -1,10 +1,15 @@
 package cache_test
 
 import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
 	"testing"
 
 	"github.com/creachadair/mds/cache"
 	"github.com/creachadair/mds/cache/internal/cachetest"
+	"github.com/creachadair/mds/mtest"
 	gocmp "github.com/google/go-cmp/cmp"
 )
 
@@ -98,6 +103,16 @@ func TestLRU(t *testing.T) {
 			"get k2 = fghij67890 true", // we still have the old value for k2
 		)
 		wantVic(t)
+
+		err := c.PutErr("k2", "1aaaa2bbbb3cccc4ddde5eeee6ffff")
+		if !errors.Is(err, cache.ErrTooLarge) {
+			t.Errorf("PutErr: got %v, want %v", err, cache.ErrTooLarge)
+		}
+		if err := c.PutErr("k2", "fghij67890"); err != nil {
+			t.Errorf("PutErr: unexpected error: %v", err)
+		}
+		wantVic(t, "k2") // replacing the value removes the old one
+		victims = nil
 	})
 
 	t.Run("Remove", func(t *testing.T) {
@@ -117,3 +132,252 @@ func TestLRU(t *testing.T) {
 		wantVic(t, "k6", "k2", "k3")
 	})
 }
+
+func TestLRURandom(t *testing.T) {
+	for _, limit := range []int64{1, 10, 50, 200} {
+		t.Run(fmt.Sprint(limit), func(t *testing.T) {
+			rng := rand.New(rand.NewPCG(uint64(limit), 1))
+			c := cache.New(cache.LRU[string, string](limit).WithSize(cache.Length))
+			m := cachetest.NewModel(limit, cache.Length, cachetest.LRUModel())
+			cachetest.Run(t, c, cachetest.Random(rng, m, 2000, 24)...)
+		})
+	}
+	t.Run("Count", func(t *testing.T) {
+		rng := rand.New(rand.NewPCG(2, 3))
+		c := cache.New(cache.LRU[string, string](7))
+		m := cachetest.NewModel(7, nil, cachetest.LRUModel())
+		cachetest.Run(t, c, cachetest.Random(rng, m, 2000, 12)...)
+	})
+}
+
+func TestMaxEntries(t *testing.T) {
+	var victims []string
+	c := cache.New(cache.LRU[string, string](20).
+		WithSize(cache.Length).
+		WithMaxEntries(3).
+		OnEvict(func(key, _ string) {
+			victims = append(victims, key)
+		}),
+	)
+
+	cachetest.Run(t, c,
+		// Fill to the entry limit, well under the size limit.
+		"put k1 a = true",
+		"put k2 b = true",
+		"put k3 c = true",
+		"len = 3", "size = 3",
+
+		// Exceed the entry limit.
+		"put k4 d = true",
+		"len = 3", "size = 3",
+
+		// Replacing an existing entry does not count against the limit.
+		"put k2 bb = true",
+		"len = 3", "size = 4",
+
+		// Exceed the size limit before the entry limit.
+		"put k5 0123456789abcdefgh = true",
+		"len = 2", "size = 20",
+	)
+	if diff := gocmp.Diff(victims, []string{"k1", "k2", "k3", "k4"}); diff != "" {
+		t.Errorf("Victims (-got, +want):\n%s", diff)
+	}
+
+	mtest.MustPanic(t, func() { cache.New(cache.LRU[string, string](5).WithMaxEntries(-1)) })
+}
+
+func TestDeferredEvict(t *testing.T) {
+	var c *cache.Cache[string, int]
+	var victims []string
+	c = cache.New(cache.LRU[string, int](3).
+		WithDeferredEvict(true).
+		OnEvict(func(key string, val int) {
+			// A deferred callback may call into the cache without deadlock.
+			if c.Has(key) {
+				t.Errorf("Evict %q: key is still present", key)
+			}
+			victims = append(victims, fmt.Sprintf("%s:%d", key, val))
+		}),
+	)
+
+	for i, key := range []string{"a", "b", "c", "d", "a", "e"} {
+		c.Put(key, i)
+	}
+	if diff := gocmp.Diff(victims, []string{"a:0", "b:1", "c:2"}); diff != "" {
+		t.Errorf("Victims (-got, +want):\n%s", diff)
+	}
+
+	victims = nil
+	c.Remove("d")
+	c.Clear()
+	if diff := gocmp.Diff(victims, []string{"d:3", "a:4", "e:5"}); diff != "" {
+		t.Errorf("Victims (-got, +want):\n%s", diff)
+	}
+
+	t.Run("Concurrent", func(t *testing.T) {
+		const numWorkers = 8
+		const numPuts = 500
+
+		var μ sync.Mutex
+		last := make(map[string]int) // :: key → most recent value evicted
+		var numEvicted int
+		c := cache.New(cache.LRU[string, int](10).
+			WithDeferredEvict(true).
+			OnEvict(func(key string, val int) {
+				μ.Lock()
+				defer μ.Unlock()
+				numEvicted++
+				if old, ok := last[key]; ok && val <= old {
+					t.Errorf("Evict %q: got value %d after %d", key, val, old)
+				}
+				last[key] = val
+			}),
+		)
+
+		// Each worker owns its own keys, and stores increasing values in them,
+		// so the values evicted for each key must also be increasing.
+		var wg sync.WaitGroup
+		for w := range numWorkers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range numPuts {
+					c.Put(fmt.Sprintf("w%d-%d", w, i%5), i)
+				}
+			}()
+		}
+		wg.Wait()
+		c.Clear()
+
+		if want := numWorkers * numPuts; numEvicted != want {
+			t.Errorf("Got %d evictions, want %d", numEvicted, want)
+		}
+	})
+}
+
+func TestTiered(t *testing.T) {
+	var victims []string
+	upper := cache.New(cache.LRU[string, string](2).
+		OnEvict(func(key, _ string) { victims = append(victims, "U:"+key) }))
+	lower := cache.New(cache.LRU[string, string](10).
+		WithSize(cache.Length).
+		OnEvict(func(key, _ string) { victims = append(victims, "L:"+key) }))
+	c := cache.NewTiered(upper, lower)
+
+	wantVic := func(t *testing.T, want ...string) {
+		t.Helper()
+		if diff := gocmp.Diff(victims, want); diff != "" {
+			t.Errorf("Victims (-got, +want):\n%s", diff)
+		}
+		victims = nil
+	}
+	wantLen := func(t *testing.T, hi, lo int) {
+		t.Helper()
+		if got := c.Upper().Len(); got != hi {
+			t.Errorf("Upper len: got %d, want %d", got, hi)
+		}
+		if got := c.Lower().Len(); got != lo {
+			t.Errorf("Lower len: got %d, want %d", got, lo)
+		}
+	}
+
+	// Writes go through to both tiers.
+	for _, key := range []string{"a", "b", "c"} {
+		if !c.Put(key, key+key) {
+			t.Errorf("Put %q: got false, want true", key)
+		}
+	}
+	wantLen(t, 2, 3)
+	wantVic(t, "U:a")
+
+	// A hit in the lower tier is promoted to the upper tier.
+	if !upper.Has("b") || upper.Has("a") || !c.Has("a") {
+		t.Error("Has: wrong presence before promotion")
+	}
+	if v, ok := c.Get("a"); !ok || v != "aa" {
+		t.Errorf(`Get("a"): got (%q, %v), want ("aa", true)`, v, ok)
+	}
+	if !upper.Has("a") {
+		t.Error(`Get("a") did not promote to the upper tier`)
+	}
+	wantLen(t, 2, 3)
+	wantVic(t, "U:b")
+
+	// A miss in both tiers reports false.
+	if v, ok := c.Get("q"); ok {
+		t.Errorf(`Get("q"): got (%q, %v), want ("", false)`, v, ok)
+	}
+
+	// A value too large for the lower tier is not stored, and neither tier is
+	// modified.
+	if err := c.PutErr("a", "0123456789abc"); !errors.Is(err, cache.ErrTooLarge) {
+		t.Errorf("PutErr: got %v, want %v", err, cache.ErrTooLarge)
+	}
+	if v, ok := c.Get("a"); !ok || v != "aa" {
+		t.Errorf(`Get("a"): got (%q, %v), want ("aa", true)`, v, ok)
+	}
+	wantVic(t)
+
+	// A value too large for the upper tier is stored only in the lower tier,
+	// and does not leave a stale value in the upper tier.
+	big := cache.NewTiered(
+		cache.New(cache.LRU[string, string](2).WithSize(cache.Length)),
+		cache.New(cache.LRU[string, string](10).WithSize(cache.Length)),
+	)
+	big.Put("x", "1")
+	if !big.Put("x", "123") {
+		t.Error(`Put("x"): got false, want true`)
+	}
+	if big.Upper().Has("x") {
+		t.Error(`Upper has "x": got true, want false`)
+	}
+	if v, ok := big.Get("x"); !ok || v != "123" {
+		t.Errorf(`Get("x"): got (%q, %v), want ("123", true)`, v, ok)
+	}
+
+	// Remove and Clear affect both tiers.
+	if !c.Remove("b") {
+		t.Error(`Remove("b"): got false, want true`)
+	}
+	if c.Remove("b") {
+		t.Error(`Remove("b"): got true, want false`)
+	}
+	wantVic(t, "L:b")
+	c.Clear()
+	wantLen(t, 0, 0)
+	wantVic(t, "U:c", "U:a", "L:c", "L:a")
+
+	mtest.MustPanic(t, func() { cache.NewTiered(upper, nil) })
+	mtest.MustPanic(t, func() { cache.NewTiered(upper, upper) })
+
+	t.Run("Deferred", func(t *testing.T) {
+		var tc *cache.Tiered[string, int]
+		var victims []string
+		onEvict := func(tier string) func(string, int) {
+			return func(key string, val int) {
+				// A deferred callback may call into the Tiered without deadlock.
+				tc.Has(key)
+				victims = append(victims, fmt.Sprintf("%s:%s:%d", tier, key, val))
+			}
+		}
+		tc = cache.NewTiered(
+			cache.New(cache.LRU[string, int](1).WithDeferredEvict(true).OnEvict(onEvict("U"))),
+			cache.New(cache.LRU[string, int](2).WithDeferredEvict(true).OnEvict(onEvict("L"))),
+		)
+		tc.Put("a", 1)
+		tc.Put("b", 2)
+		tc.Put("c", 3)
+		tc.Get("b")
+		tc.Remove("c")
+		tc.Clear()
+		if diff := gocmp.Diff(victims, []string{
+			"U:a:1",          // Put b
+			"U:b:2", "L:a:1", // Put c
+			"U:c:3",          // Get b
+			"L:c:3",          // Remove c
+			"U:b:2", "L:b:2", // Clear
+		}); diff != "" {
+			t.Errorf("Victims (-got, +want):\n%s", diff)
+		}
+	})
+}
@@ -4,6 +4,8 @@ package cachetest
 import (
 	"errors"
 	"fmt"
+	"math/rand/v2"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
@@ -188,3 +190,173 @@ func parseInsn(s string) (insn, error) {
 	}
 	return out, nil
 }
+
+// A Model is a reference model of a cache, used to compute the expected
+// results of a random workload (see [Random]). A Model keeps its own
+// accounting of the cache contents, and uses a Store to simulate the eviction
+// policy of the cache under test.
+type Model struct {
+	limit  int64
+	sizeOf func(string) int64
+	store  cache.Store[string, string]
+
+	values map[string]string
+	size   int64
+}
+
+// NewModel constructs an empty model of a cache with the given capacity limit
+// and size function, whose eviction policy is simulated by store. If sizeOf ==
+// nil, each entry has size 1.  The store should be a simple and obviously
+// correct implementation of the policy, rather than the one under test.
+func NewModel(limit int64, sizeOf func(string) int64, store cache.Store[string, string]) *Model {
+	if sizeOf == nil {
+		sizeOf = func(string) int64 { return 1 }
+	}
+	return &Model{limit: limit, sizeOf: sizeOf, store: store, values: make(map[string]string)}
+}
+
+func (m *Model) has(key string) bool { _, ok := m.store.Check(key); return ok }
+
+func (m *Model) get(key string) (string, bool) { return m.store.Access(key) }
+
+func (m *Model) put(key, val string) bool {
+	vsize := m.sizeOf(val)
+	if vsize > m.limit {
+		return false
+	}
+	m.remove(key)
+	for m.size+vsize > m.limit {
+		ek, ev := m.store.Evict()
+		delete(m.values, ek)
+		m.size -= m.sizeOf(ev)
+	}
+	m.store.Store(key, val)
+	m.values[key] = val
+	m.size += vsize
+	return true
+}
+
+func (m *Model) remove(key string) bool {
+	old, ok := m.values[key]
+	if ok {
+		m.store.Remove(key)
+		delete(m.values, key)
+		m.size -= m.sizeOf(old)
+	}
+	return ok
+}
+
+func (m *Model) clear() {
+	for len(m.values) != 0 {
+		ek, _ := m.store.Evict()
+		delete(m.values, ek)
+	}
+	m.size = 0
+}
+
+// Random generates a random test program of n instructions for [Run], whose
+// operations use up to nkeys distinct keys. The expected result of each
+// instruction is computed by applying it to m, so that running the program on
+// a cache configured like m checks that the cache agrees with the model.
+func Random(rng *rand.Rand, m *Model, n, nkeys int) []string {
+	key := func() string { return "k" + strconv.Itoa(rng.IntN(nkeys)) }
+	value := func() string {
+		buf := make([]byte, 1+rng.IntN(8))
+		for i := range buf {
+			buf[i] = byte('a' + rng.IntN(26))
+		}
+		return string(buf)
+	}
+
+	prog := make([]string, 0, n)
+	for range n {
+		var line string
+		switch op := rng.IntN(100); {
+		case op < 40:
+			k, v := key(), value()
+			line = fmt.Sprintf("put %s %s = %v", k, v, m.put(k, v))
+		case op < 65:
+			k := key()
+			v, ok := m.get(k)
+			if v == "" {
+				v = "''"
+			}
+			line = fmt.Sprintf("get %s = %s %v", k, v, ok)
+		case op < 80:
+			k := key()
+			line = fmt.Sprintf("has %s = %v", k, m.has(k))
+		case op < 90:
+			k := key()
+			line = fmt.Sprintf("remove %s = %v", k, m.remove(k))
+		case op < 94:
+			line = fmt.Sprintf("len = %d", len(m.values))
+		case op < 98:
+			line = fmt.Sprintf("size = %d", m.size)
+		default:
+			m.clear()
+			line = "clear"
+		}
+		prog = append(prog, line)
+	}
+	return prog
+}
+
+// LRUModel returns a simple implementation of a least-recently used eviction
+// policy, suitable for use as the store of a [Model].
+func LRUModel() cache.Store[string, string] { return new(lruModel) }
+
+// lruModel is a naive LRU store that keeps entries in a slice ordered from
+// least to most recently accessed.
+type lruModel struct {
+	entries []lruEntry
+}
+
+type lruEntry struct{ key, value string }
+
+func (s *lruModel) find(key string) int {
+	for i, e := range s.entries {
+		if e.key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *lruModel) Check(key string) (string, bool) {
+	if i := s.find(key); i >= 0 {
+		return s.entries[i].value, true
+	}
+	return "", false
+}
+
+func (s *lruModel) Access(key string) (string, bool) {
+	i := s.find(key)
+	if i < 0 {
+		return "", false
+	}
+	e := s.entries[i]
+	s.entries = append(slices.Delete(s.entries, i, i+1), e)
+	return e.value, true
+}
+
+func (s *lruModel) Store(key, val string) {
+	if s.find(key) >= 0 {
+		panic(fmt.Sprintf("lru model: unexpected key %v", key))
+	}
+	s.entries = append(s.entries, lruEntry{key, val})
+}
+
+func (s *lruModel) Remove(key string) {
+	if i := s.find(key); i >= 0 {
+		s.entries = slices.Delete(s.entries, i, i+1)
+	}
+}
+
+func (s *lruModel) Evict() (string, string) {
+	if len(s.entries) == 0 {
+		panic("lru model: no entries left")
+	}
+	e := s.entries[0]
+	s.entries = slices.Delete(s.entries, 0, 1)
+	return e.key, e.value
+}
@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+)
+
+// A Tiered is a two-level cache that combines an upper [Cache], typically
+// small and fast, with a lower Cache, typically larger or slower. For example,
+// the upper tier may be a small LRU cache of hot entries, in front of a much
+// larger cache whose store is an adapter to a remote service.
+//
+// Reads are served from the upper tier if possible, and otherwise from the
+// lower tier; a value found only in the lower tier is promoted to the upper
+// tier. Writes go through to both tiers. Each tier evicts entries according to
+// its own configuration, so an entry may remain in one tier after it has been
+// evicted from the other.
+//
+// A Tiered is safe for concurrent access by multiple goroutines. Operations on
+// a Tiered are serialized, so that a promotion cannot overwrite a concurrent
+// update. The eviction callbacks of the tiers must not call methods of the
+// Tiered, unless eviction callbacks are deferred (see
+// [Config.WithDeferredEvict]). Deferred callbacks are run after the Tiered
+// is unlocked.
+type Tiered[Key comparable, Value any] struct {
+	μ     sync.Mutex
+	upper *Cache[Key, Value]
+	lower *Cache[Key, Value]
+}
+
+// NewTiered constructs a new tiered cache with the specified upper and lower
+// tiers, which must be distinct and non-nil or NewTiered will panic. The
+// Tiered takes ownership of the tiers; the caller should not modify them
+// directly after NewTiered returns.
+func NewTiered[K comparable, V any](upper, lower *Cache[K, V]) *Tiered[K, V] {
+	if upper == nil || lower == nil {
+		panic("cache: nil tier")
+	} else if upper == lower {
+		panic("cache: tiers must be distinct")
+	}
+	return &Tiered[K, V]{upper: upper, lower: lower}
+}
+
+// Upper returns the upper tier of t. This is intended for inspecting the
+// tier, for example to check its size; the caller should not modify it.
+func (t *Tiered[K, V]) Upper() *Cache[K, V] { return t.upper }
+
+// Lower returns the lower tier of t, as for [Tiered.Upper].
+func (t *Tiered[K, V]) Lower() *Cache[K, V] { return t.lower }
+
+// Has reports whether a value for key is present in either tier of t. This
+// does not count as an access of the value for cache accounting.
+func (t *Tiered[K, V]) Has(key K) bool {
+	t.μ.Lock()
+	defer t.μ.Unlock()
+	return t.upper.Has(key) || t.lower.Has(key)
+}
+
+// Get reports whether key is present in t, and if so returns the
+// corresponding cached value. If the value is found only in the lower tier, it
+// is promoted to the upper tier, space permitting. This counts as an access of
+// the value in each tier where it is found.
+func (t *Tiered[K, V]) Get(key K) (V, bool) {
+	defer t.dispatch()
+	t.μ.Lock()
+	defer t.μ.Unlock()
+	if v, ok := t.upper.Get(key); ok {
+		return v, true
+	}
+	v, ok := t.lower.Get(key)
+	if ok {
+		t.upper.putErr(key, v)
+	}
+	return v, ok
+}
+
+// Put adds or replaces the value for key in both tiers of t, and reports
+// whether the value was successfully stored. See [Tiered.PutErr].
+func (t *Tiered[K, V]) Put(key K, val V) bool { return t.PutErr(key, val) == nil }
+
+// PutErr adds or replaces the value for key in both tiers of t, and reports an
+// error if the value could not be stored.
+//
+// The value is written to the lower tier first. If the lower tier does not
+// accept it, PutErr reports the error from the lower tier, and neither tier is
+// modified. If the lower tier accepts the value but the upper tier does not,
+// the value is stored only in the lower tier, any existing value for key is
+// removed from the upper tier, and PutErr reports success.
+func (t *Tiered[K, V]) PutErr(key K, val V) error {
+	defer t.dispatch()
+	t.μ.Lock()
+	defer t.μ.Unlock()
+	if err := t.lower.putErr(key, val); err != nil {
+		return fmt.Errorf("lower tier: %w", err)
+	}
+	if t.upper.putErr(key, val) != nil {
+		t.upper.remove(key) // do not leave a stale value
+	}
+	return nil
+}
+
+// Remove removes the specified key from both tiers of t, and reports whether
+// a value had been cached for that key in either tier.
+func (t *Tiered[K, V]) Remove(key K) bool {
+	defer t.dispatch()
+	t.μ.Lock()
+	defer t.μ.Unlock()
+	hi := t.upper.remove(key)
+	lo := t.lower.remove(key)
+	return hi || lo
+}
+
+// Clear discards the complete contents of both tiers of t, leaving them empty.
+func (t *Tiered[K, V]) Clear() {
+	defer t.dispatch()
+	t.μ.Lock()
+	defer t.μ.Unlock()
+	t.upper.clear()
+	t.lower.clear()
+}
+
+// dispatch delivers any pending evicted entries of both tiers to their
+// eviction callbacks. The caller must not hold t.μ.
+func (t *Tiered[K, V]) dispatch() {
+	t.upper.dispatch()
+	t.lower.dispatch()
+}
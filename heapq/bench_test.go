@@ -0,0 +1,56 @@
+package heapq_test
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/mds/heapq"
+)
+
+const benchSeed = 8675309
+
+// Queue sizes for benchmarking pop-heavy workloads.
+var benchSizes = []int{1_000, 100_000}
+
+func BenchmarkPop(b *testing.B) {
+	rng := rand.New(rand.NewPCG(benchSeed, benchSeed))
+
+	for _, n := range benchSizes {
+		ints := make([]int, n)
+		strs := make([]string, n)
+		for i := range ints {
+			ints[i] = rng.IntN(1 << 30)
+
+			// Use a common prefix so that comparisons are not trivial.
+			strs[i] = fmt.Sprintf("%s/%010d", strings.Repeat("x", 32), ints[i])
+		}
+
+		b.Run(fmt.Sprintf("int/n=%d", n), func(b *testing.B) {
+			benchPop(b, cmp.Compare[int], ints)
+		})
+		b.Run(fmt.Sprintf("string/n=%d", n), func(b *testing.B) {
+			benchPop(b, strings.Compare, strs)
+		})
+	}
+}
+
+// benchPop measures the cost of popping all the elements of a queue
+// initialized with the contents of input, counting comparisons.
+func benchPop[T any](b *testing.B, compare func(a, b T) int, input []T) {
+	var ncmp int
+	q := heapq.New(func(a, b T) int { ncmp++; return compare(a, b) })
+	b.ResetTimer()
+	for range b.N {
+		b.StopTimer()
+		q.Set(input)
+		ncmp = 0
+		b.StartTimer()
+		for !q.IsEmpty() {
+			q.Pop()
+		}
+	}
+	b.ReportMetric(float64(ncmp)/float64(len(input)), "cmp/pop")
+}
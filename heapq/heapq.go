@@ -1,4 +1,5 @@
-// Package heapq implements a generic heap-structured priority queue.
+// Package heapq implements a generic heap-structured priority queue, and a
+// hierarchical timing wheel for coarse-grained deadlines.
 package heapq
 
 // A Queue is a heap-structured priority queue. The contents of a Queue are
@@ -37,7 +38,7 @@ func New[T any](cmp func(a, b T) int) *Queue[T] { return &Queue[T]{cmp: cmp, mov
 //
 // The resulting queue takes ownership of the slice, and the caller should not
 // access the contents data after the call unless the queue will no longer be
-// used.
+// used. Use [Queue.TakeData] to reclaim the storage from the queue.
 func NewWithData[T any](cmp func(a, b T) int, data []T) *Queue[T] {
 	q := &Queue[T]{data: data, cmp: cmp, move: nmove[T]}
 	for i := len(q.data) / 2; i >= 0; i-- {
@@ -166,22 +167,89 @@ func (q *Queue[T]) Each(f func(T) bool) {
 	}
 }
 
+// Sorted is a range function that calls f with each value in q in order by
+// the comparison function of q, that is, the order in which Pop would deliver
+// them. If f returns false, Sorted returns immediately. Unlike repeated calls
+// to Pop, Sorted does not modify q, but q must not be modified while it is
+// running.
+//
+// Sorted keeps an auxiliary heap of offsets into q, so visiting the first k
+// values takes O(k lg k) time and O(k) space, independent of the size of q.
+func (q *Queue[T]) Sorted(f func(T) bool) {
+	if len(q.data) == 0 {
+		return
+	}
+	next := New(func(i, j int) int { return q.cmp(q.data[i], q.data[j]) })
+	next.Add(0)
+	for !next.IsEmpty() {
+		i, _ := next.Pop()
+		if !f(q.data[i]) {
+			return
+		}
+		// The children of i are ordered after it, but their relation to the
+		// other values in next is unknown.
+		if lc := 2*i + 1; lc < len(q.data) {
+			next.Add(lc)
+			if lc+1 < len(q.data) {
+				next.Add(lc + 1)
+			}
+		}
+	}
+}
+
 // Clear discards all the entries in q, leaving it empty.
 func (q *Queue[T]) Clear() { q.data = q.data[:0] }
 
+// TakeData removes and returns the storage of q without copying, leaving q
+// empty with no storage. The elements of the result are in heap order, so
+// the first element (if any) is the minimum. After TakeData returns, q no
+// longer refers to the slice and may be reused; the caller owns the result.
+//
+// This is the counterpart of [NewWithData]. To resume queue operations on
+// the same storage, pass the slice (or a modification of it) to NewWithData,
+// which restores heap order in time proportional to its length.
+func (q *Queue[T]) TakeData() []T {
+	out := q.data
+	q.data = nil
+	return out
+}
+
 // pop removes and returns the value at index i of the heap, after restoring
 // heap order. Precondition: i < len(q.data).
+//
+// Rather than moving the last element into the vacated slot and pushing it
+// down (which costs two comparisons per level), pop sifts the vacancy down to
+// a leaf along the path of smaller children, at one comparison per level, and
+// then fills it with the last element and pushes that up.  Since the last
+// element of a heap usually belongs near the bottom, the final push rarely
+// travels far, so this saves nearly half the comparisons of a pop.
 func (q *Queue[T]) pop(i int) T {
 	out := q.data[i]
 	n := len(q.data) - 1
-	if n == 0 {
-		q.data = q.data[:0]
-	} else {
-		q.data[i], q.data[n] = q.data[n], out
-		q.move(q.data[i], i) // N.B. we do not report a move of out.
+	if i == n {
 		q.data = q.data[:n]
-		q.pushDown(i)
+		return out
+	}
+	last := q.data[n]
+	q.data[n] = out // N.B. we do not report a move of out.
+	q.data = q.data[:n]
+
+	// Sift the vacancy at i down to a leaf.
+	for {
+		min := 2*i + 1
+		if min >= n {
+			break
+		}
+		if rc := min + 1; rc < n && q.cmp(q.data[rc], q.data[min]) < 0 {
+			min = rc
+		}
+		q.data[i] = q.data[min]
+		q.move(q.data[i], i)
+		i = min
 	}
+	q.data[i] = last
+	q.move(last, i)
+	q.pushUp(i)
 	return out
 }
 
@@ -189,7 +257,7 @@ func (q *Queue[T]) pop(i int) T {
 // ordered relative to its parent, and returns the resulting heap index.
 func (q *Queue[T]) pushUp(i int) int {
 	for i > 0 {
-		par := i / 2
+		par := (i - 1) / 2
 		if q.cmp(q.data[i], q.data[par]) >= 0 {
 			break
 		}
@@ -3,6 +3,7 @@ package heapq_test
 import (
 	"cmp"
 	"math/rand/v2"
+	"slices"
 	"sort"
 	"testing"
 
@@ -76,9 +77,9 @@ func runTests(t *testing.T, q *heapq.Queue[int]) {
 	checkAdd(5, 0)
 	check(5, 10)
 	checkAdd(3, 0)
-	check(3, 5, 10)
+	check(3, 10, 5)
 	checkAdd(4, 1)
-	check(3, 4, 10, 5)
+	check(3, 4, 5, 10)
 	checkPop(3, true)
 
 	checkPop(4, true)
@@ -135,6 +136,28 @@ func TestOrder(t *testing.T) {
 		}
 	})
 
+	t.Run("Mixed", func(t *testing.T) {
+		// Interleave additions and removals, so that additions land at
+		// positions throughout the heap. Compare to a sorted shadow.
+		q := heapq.New(intCompare)
+		var shadow []int
+		for range 200 {
+			for range rand.IntN(20) {
+				z := rand.IntN(inputRange)
+				q.Add(z)
+				shadow = append(shadow, z)
+			}
+			sort.Ints(shadow)
+			for range rand.IntN(q.Len() + 1) {
+				got, _ := q.Pop()
+				if got != shadow[0] {
+					t.Fatalf("Pop: got %d, want %d", got, shadow[0])
+				}
+				shadow = shadow[1:]
+			}
+		}
+	})
+
 	t.Run("Reorder", func(t *testing.T) {
 		q := heapq.New(intCompare)
 		q.Set([]int{17, 3, 11, 2, 7, 5, 13})
@@ -154,6 +177,46 @@ func TestOrder(t *testing.T) {
 	})
 }
 
+func TestSorted(t *testing.T) {
+	q := heapq.New(intCompare)
+	for range q.Sorted {
+		t.Error("Sorted: unexpected value in empty queue")
+	}
+
+	input := make([]int, 500)
+	for i := range input {
+		input[i] = rand.IntN(200) - 100
+	}
+	q.Set(input)
+	before := slices.Collect(q.Each)
+
+	got := slices.Collect(q.Sorted)
+	want := slices.Sorted(slices.Values(input))
+	if diff := gocmp.Diff(got, want); diff != "" {
+		t.Errorf("Sorted (-got, +want):\n%s", diff)
+	}
+	if diff := gocmp.Diff(slices.Collect(q.Each), before); diff != "" {
+		t.Errorf("Queue modified by Sorted (-got, +want):\n%s", diff)
+	}
+
+	// Stopping early yields a prefix of the order.
+	var prefix []int
+	for v := range q.Sorted {
+		if len(prefix) == 10 {
+			break
+		}
+		prefix = append(prefix, v)
+	}
+	if diff := gocmp.Diff(prefix, want[:10]); diff != "" {
+		t.Errorf("Sorted prefix (-got, +want):\n%s", diff)
+	}
+
+	// The order agrees with Pop.
+	if diff := gocmp.Diff(extract(q), want); diff != "" {
+		t.Errorf("Extract (-got, +want):\n%s", diff)
+	}
+}
+
 func TestNewWithData(t *testing.T) {
 	const bufSize = 100 // N.B. must be even, so we can fill halves
 
@@ -190,6 +253,42 @@ func TestNewWithData(t *testing.T) {
 	}
 }
 
+func TestTakeData(t *testing.T) {
+	buf := make([]int, 0, 20)
+	q := heapq.NewWithData(intCompare, buf)
+	for _, z := range []int{8, 3, 5, 1, 9, 2} {
+		q.Add(z)
+	}
+
+	data := q.TakeData()
+	if !q.IsEmpty() {
+		t.Errorf("After TakeData: queue has %d elements, want 0", q.Len())
+	}
+	if &data[0] != &buf[:1][0] {
+		t.Error("TakeData did not return the original storage")
+	}
+	if len(data) != 6 || data[0] != 1 {
+		t.Errorf("TakeData: got %v, want 6 elements starting with 1", data)
+	}
+
+	// The queue is usable after its storage is taken.
+	q.Add(4)
+	if got, ok := q.Pop(); !ok || got != 4 {
+		t.Errorf("Pop: got (%v, %v), want (4, true)", got, ok)
+	}
+
+	// The storage can be handed back to a new queue.
+	q = heapq.NewWithData(intCompare, data)
+	var got []int
+	for !q.IsEmpty() {
+		v, _ := q.Pop()
+		got = append(got, v)
+	}
+	if diff := gocmp.Diff(got, []int{1, 2, 3, 5, 8, 9}); diff != "" {
+		t.Errorf("Queue contents (-got, +want):\n%s", diff)
+	}
+}
+
 func TestSort(t *testing.T) {
 	longIn := make([]int, 50)
 	for i := range longIn {
@@ -0,0 +1,157 @@
+package heapq
+
+import "time"
+
+const (
+	wheelBits = 6              // bits of tick number per wheel level
+	wheelSize = 1 << wheelBits // slots per wheel level
+	wheelMask = wheelSize - 1
+)
+
+// A Wheel is a hierarchical timing wheel, a collection of values with
+// deadlines that supports O(1) insertion, for use when there are many
+// deadlines and they need only be observed at a coarse granularity.
+//
+// Time is divided into ticks of a fixed duration, measured from a start time
+// given when the wheel is constructed. Unlike a [Queue] ordered by deadline,
+// a Wheel does not distinguish the order of deadlines that fall within the
+// same tick. Add a value with [Wheel.Add], and remove the values whose
+// deadlines have passed with [Wheel.PopReady].
+//
+// A zero Wheel is not ready for use; use [NewWheel] to construct one.
+type Wheel[T any] struct {
+	start time.Time
+	tick  time.Duration
+	cur   int64 // the next tick to be processed by PopReady
+
+	// The ith level holds the pending entries whose deadline tick agrees with
+	// cur on all but the low-order (i+1)·wheelBits bits, but not fewer.
+	levels []*wheelLevel[T]
+	ready  []T // entries whose deadline has passed
+	n      int // total number of entries, including ready
+}
+
+type wheelLevel[T any] struct {
+	n     int // total number of entries in all slots
+	slots [wheelSize][]wheelEntry[T]
+}
+
+type wheelEntry[T any] struct {
+	due   int64 // the deadline tick
+	value T
+}
+
+// NewWheel constructs a new empty wheel that divides time after start into
+// ticks of the given duration. NewWheel will panic if tick ≤ 0.
+func NewWheel[T any](start time.Time, tick time.Duration) *Wheel[T] {
+	if tick <= 0 {
+		panic("tick out of range")
+	}
+	return &Wheel[T]{start: start, tick: tick}
+}
+
+// Len reports the number of values in w, including any whose deadline has
+// passed but which have not yet been removed by PopReady.
+func (w *Wheel[T]) Len() int { return w.n }
+
+// Add adds v to w with the specified deadline. The deadline is rounded up to
+// the next tick boundary, so that v is never ready before its deadline.
+// Add takes constant time.
+func (w *Wheel[T]) Add(deadline time.Time, v T) {
+	var due int64
+	if d := deadline.Sub(w.start); d > 0 {
+		due = int64((d + w.tick - 1) / w.tick)
+	}
+	w.insert(wheelEntry[T]{due: due, value: v})
+	w.n++
+}
+
+// PopReady removes and returns all the values of w whose deadlines are at or
+// before now. Values due in earlier ticks precede those due in later ticks,
+// but the order of values due in the same tick, or added after their deadline
+// tick had already been processed, is unspecified. If no values are ready,
+// PopReady returns nil.
+//
+// The cost of PopReady is proportional to the number of values returned, plus
+// the number of ticks elapsed since the previous call, amortized over the
+// entries of the wheel. Elapsed ticks with no pending values are skipped.
+func (w *Wheel[T]) PopReady(now time.Time) []T {
+	if d := now.Sub(w.start); d >= 0 {
+		w.advance(int64(d / w.tick))
+	}
+	out := w.ready
+	w.ready = nil
+	w.n -= len(out)
+	return out
+}
+
+// insert adds e to the level and slot of w appropriate to its deadline.
+func (w *Wheel[T]) insert(e wheelEntry[T]) {
+	if e.due < w.cur {
+		w.ready = append(w.ready, e.value)
+		return
+	}
+
+	// Find the lowest level at which the deadline agrees with the current
+	// tick on all the bits above that level.
+	l := 0
+	for e.due>>(wheelBits*(l+1)) != w.cur>>(wheelBits*(l+1)) {
+		l++
+	}
+	for len(w.levels) <= l {
+		w.levels = append(w.levels, new(wheelLevel[T]))
+	}
+	lv := w.levels[l]
+	i := (e.due >> (wheelBits * l)) & wheelMask
+	lv.slots[i] = append(lv.slots[i], e)
+	lv.n++
+}
+
+// advance processes all the ticks of w up to and including target, moving the
+// entries whose deadlines have passed to the ready list.
+func (w *Wheel[T]) advance(target int64) {
+	for w.cur <= target {
+		// If the lowest levels are empty, nothing happens until the tick at
+		// which the first non-empty level next cascades.
+		empty := 0
+		for empty < len(w.levels) && w.levels[empty].n == 0 {
+			empty++
+		}
+		if empty == len(w.levels) {
+			w.cur = target + 1 // no pending entries
+			return
+		} else if empty > 0 {
+			span := int64(1) << (wheelBits * empty)
+			next := (w.cur + span - 1) &^ (span - 1)
+			if next > target {
+				w.cur = target + 1
+				return
+			}
+			w.cur = next
+		}
+
+		// At the boundary of a higher level, redistribute the entries in its
+		// current slot to the lower levels.
+		for l := 1; l < len(w.levels) && w.cur&(1<<(wheelBits*l)-1) == 0; l++ {
+			lv := w.levels[l]
+			i := (w.cur >> (wheelBits * l)) & wheelMask
+			es := lv.slots[i]
+			lv.slots[i] = nil
+			lv.n -= len(es)
+			for _, e := range es {
+				w.insert(e)
+			}
+		}
+
+		// The current slot of the lowest level holds the entries due now.
+		lv := w.levels[0]
+		i := w.cur & wheelMask
+		for _, e := range lv.slots[i] {
+			w.ready = append(w.ready, e.value)
+		}
+		lv.n -= len(lv.slots[i])
+		clear(lv.slots[i])
+		lv.slots[i] = lv.slots[i][:0]
+		w.cur++
+	}
+}
@@ -0,0 +1,122 @@
+package heapq_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/creachadair/mds/heapq"
+	"github.com/creachadair/mds/mtest"
+	gocmp "github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestWheel(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(ms int) time.Time { return start.Add(time.Duration(ms) * time.Millisecond) }
+
+	w := heapq.NewWheel[string](start, 10*time.Millisecond)
+	if got := w.PopReady(at(100)); got != nil {
+		t.Errorf("PopReady empty: got %q, want nil", got)
+	}
+
+	w.Add(at(250), "c")
+	w.Add(at(105), "a")
+	w.Add(at(1_000_000), "e")
+	w.Add(at(120), "b")
+	w.Add(at(5000), "d")
+	if n := w.Len(); n != 5 {
+		t.Errorf("Len: got %d, want 5", n)
+	}
+
+	tests := []struct {
+		now  int
+		want []string
+	}{
+		{105, nil}, // deadlines round up to the next tick
+		{110, []string{"a"}},
+		{249, []string{"b"}},
+		{250, []string{"c"}},
+		{4999, nil},
+		{999_999, []string{"d"}},
+		{2_000_000, []string{"e"}},
+	}
+	for _, tc := range tests {
+		if diff := gocmp.Diff(w.PopReady(at(tc.now)), tc.want); diff != "" {
+			t.Errorf("PopReady(%d) (-got, +want):\n%s", tc.now, diff)
+		}
+	}
+	if n := w.Len(); n != 0 {
+		t.Errorf("Len: got %d, want 0", n)
+	}
+
+	// A value whose deadline has passed is ready at once.
+	w.Add(at(500), "late")
+	if n := w.Len(); n != 1 {
+		t.Errorf("Len: got %d, want 1", n)
+	}
+	if diff := gocmp.Diff(w.PopReady(at(2_000_000)), []string{"late"}); diff != "" {
+		t.Errorf("PopReady (-got, +want):\n%s", diff)
+	}
+
+	mtest.MustPanic(t, func() { heapq.NewWheel[int](start, 0) })
+}
+
+func TestWheelRandom(t *testing.T) {
+	const tick = time.Millisecond
+	start := time.Unix(0, 0)
+	w := heapq.NewWheel[int](start, tick)
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	var now time.Duration
+	pending := make(map[int]time.Duration) // :: id → deadline
+	for id := range 20000 {
+		switch rng.IntN(5) {
+		case 0:
+			// Advance by a varying amount, sometimes quite large.
+			now += time.Duration(rng.Int64N(int64(1) << rng.IntN(36)))
+			got := w.PopReady(start.Add(now))
+
+			var lastDue time.Duration // in ticks
+			for i, id := range got {
+				due, ok := pending[id]
+				if !ok {
+					t.Fatalf("PopReady: unexpected value %d", id)
+				} else if due > now {
+					t.Errorf("PopReady: value %d due at %v, now is %v", id, due, now)
+				}
+				if tk := (due + tick - 1) / tick; i > 0 && tk < lastDue {
+					t.Errorf("PopReady: value %d due at %v after %v", id, due, lastDue)
+				} else {
+					lastDue = tk
+				}
+				delete(pending, id)
+			}
+			for id, due := range pending {
+				if due <= now.Truncate(tick) {
+					t.Errorf("PopReady: value %d due at %v was not returned at %v", id, due, now)
+				}
+			}
+		default:
+			due := now + time.Duration(rng.Int64N(int64(1)<<rng.IntN(40)))
+			w.Add(start.Add(due), id)
+			pending[id] = due
+		}
+		if w.Len() != len(pending) {
+			t.Fatalf("Len: got %d, want %d", w.Len(), len(pending))
+		}
+	}
+	t.Logf("After %v: %d values pending", now, len(pending))
+
+	ids := w.PopReady(start.Add(now + time.Hour))
+	slices.Sort(ids)
+	want := make([]int, 0, len(pending))
+	for id := range pending {
+		want = append(want, id)
+	}
+	slices.Sort(want)
+	if diff := gocmp.Diff(ids, want, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("Final PopReady (-got, +want):\n%s", diff)
+	}
+}
@@ -0,0 +1,18 @@
+// Package augment allows other packages in this module to maintain augmented
+// data in the nodes of an [stree.Tree], without adding to the exported API of
+// package stree.
+//
+// [stree.Tree]: https://pkg.go.dev/github.com/creachadair/mds/stree#Tree
+package augment
+
+// Set installs fn as the augmentation function of tree, which must be a
+// *stree.Tree[T] for some T, and fn must be a func(x, left, right *T).
+//
+// The augmentation function is called to update the key x of a node from the
+// keys of its children, left and right, either of which may be nil. It must
+// not change the order of x relative to other keys. All the methods of the
+// tree and its cursors that modify the tree keep the augmented data up to
+// date, and a tree returned by Split or Clone has the same augmentation.
+//
+// Set is initialized by package stree.
+var Set func(tree, fn any)
@@ -0,0 +1,215 @@
+// Package interval implements an interval tree, an ordered collection of
+// half-open intervals that supports efficient overlap and stabbing queries.
+//
+// The tree is a [stree.Tree] in which each node is augmented with the
+// greatest upper bound of any interval in its subtree. This permits a
+// query to skip subtrees that cannot contain a matching interval, so that a
+// query reporting k intervals from a tree of n takes O((k+1) lg n) time in
+// the worst case.
+//
+// For example:
+//
+//	t := interval.New[int, string]()
+//	t.Add(1, 5, "a")  // [1, 5)
+//	t.Add(3, 9, "b")  // [3, 9)
+//	t.Add(6, 12, "c") // [6, 12)
+//
+//	for iv, v := range t.Stab(4) {
+//	   fmt.Println(iv, v) // {1 5} a, then {3 9} b
+//	}
+package interval
+
+import (
+	"cmp"
+	"iter"
+
+	"github.com/creachadair/mds/internal/augment"
+	"github.com/creachadair/mds/stree"
+)
+
+// An Interval is a half-open interval [Lo, Hi), containing the points p such
+// that Lo ≤ p < Hi. An interval with Lo == Hi is empty.
+type Interval[K any] struct {
+	Lo, Hi K
+}
+
+// A Tree is an interval tree mapping intervals with endpoints of type K to
+// values of type V. A Tree may contain multiple entries for the same
+// interval. A zero Tree is not ready for use; construct one with [New] or
+// [NewFunc]. A *Tree is not safe for concurrent use without external
+// synchronization.
+type Tree[K, V any] struct {
+	tree    *stree.Tree[entry[K, V]]
+	compare func(a, b K) int
+	seq     uint64 // sequence number of the next entry added
+}
+
+// An entry is the key of a node in the underlying tree.
+type entry[K, V any] struct {
+	iv  Interval[K]
+	val V
+	seq uint64 // orders entries for the same interval by when they were added
+	top K      // the greatest upper bound of any interval in this subtree
+}
+
+// New constructs a new empty Tree using the natural comparison order for an
+// ordered endpoint type.
+func New[K cmp.Ordered, V any]() *Tree[K, V] { return NewFunc[K, V](cmp.Compare) }
+
+// NewFunc constructs a new empty Tree using compare to compare endpoints,
+// where compare(a, b) must be <0 if a < b, =0 if a == b, and >0 if a > b.
+// If compare == nil, NewFunc will panic.
+func NewFunc[K, V any](compare func(a, b K) int) *Tree[K, V] {
+	if compare == nil {
+		panic("interval: nil comparison function")
+	}
+	t := &Tree[K, V]{compare: compare}
+	t.tree = stree.New(250, t.compareEntry)
+	augment.Set(t.tree, t.fix)
+	return t
+}
+
+// Len reports the number of entries in t.
+func (t *Tree[K, V]) Len() int { return t.tree.Len() }
+
+// IsEmpty reports whether t is empty.
+func (t *Tree[K, V]) IsEmpty() bool { return t.tree.IsEmpty() }
+
+// Clear discards all the entries in t, leaving it empty.
+func (t *Tree[K, V]) Clear() { t.tree.Clear() }
+
+// Add adds an entry for the interval [lo, hi) with value v to t. If t already
+// contains entries for the same interval, the new entry is ordered after
+// them. Add panics if hi < lo.
+func (t *Tree[K, V]) Add(lo, hi K, v V) {
+	if t.compare(hi, lo) < 0 {
+		panic("interval: upper bound less than lower bound")
+	}
+	t.tree.Add(entry[K, V]{iv: Interval[K]{Lo: lo, Hi: hi}, val: v, seq: t.seq, top: hi})
+	t.seq++
+}
+
+// Remove removes an entry for the interval [lo, hi) from t, and reports
+// whether such an entry was present. If so, it also returns the value of the
+// removed entry. If t contains multiple entries for the interval, which one
+// is removed is unspecified.
+func (t *Tree[K, V]) Remove(lo, hi K) (V, bool) {
+	iv := Interval[K]{Lo: lo, Hi: hi}
+	e, ok := t.tree.Ceiling(entry[K, V]{iv: iv}) // the earliest entry for iv, if any
+	if !ok || t.compareInterval(e.iv, iv) != 0 {
+		var zero V
+		return zero, false
+	}
+	t.tree.Remove(e)
+	return e.val, true
+}
+
+// Inorder is a range function that visits each entry of t in order by the
+// lower bound of its interval, then by the upper bound.
+func (t *Tree[K, V]) Inorder(yield func(Interval[K], V) bool) {
+	for e := range t.tree.Inorder {
+		if !yield(e.iv, e.val) {
+			return
+		}
+	}
+}
+
+// Stab returns an iterator over the entries of t whose intervals contain p,
+// that is, those for which Lo ≤ p < Hi, in the same order as Inorder.
+//
+// The tree must not be modified while the iterator is running.
+func (t *Tree[K, V]) Stab(p K) iter.Seq2[Interval[K], V] {
+	return func(yield func(Interval[K], V) bool) {
+		t.search(t.tree.Root(),
+			func(lo K) bool { return t.compare(lo, p) <= 0 },
+			func(hi K) bool { return t.compare(hi, p) > 0 },
+			yield)
+	}
+}
+
+// Overlaps returns an iterator over the entries of t whose intervals overlap
+// [lo, hi), that is, those having at least one point in common with it, in
+// the same order as Inorder. Empty intervals overlap nothing.
+//
+// The tree must not be modified while the iterator is running.
+func (t *Tree[K, V]) Overlaps(lo, hi K) iter.Seq2[Interval[K], V] {
+	return func(yield func(Interval[K], V) bool) {
+		if t.compare(lo, hi) >= 0 {
+			return // the query is empty
+		}
+		t.search(t.tree.Root(),
+			func(ilo K) bool { return t.compare(ilo, hi) < 0 },
+			func(ihi K) bool { return t.compare(ihi, lo) > 0 },
+			func(iv Interval[K], v V) bool {
+				if t.compare(iv.Lo, iv.Hi) == 0 {
+					return true // skip empty intervals
+				}
+				return yield(iv, v)
+			})
+	}
+}
+
+// search visits the entries of the subtree at c whose intervals satisfy both
+// loOK and hiOK in order, calling yield for each until it returns false.
+// The loOK predicate must be monotone: once false for a lower bound, it must
+// be false for all greater ones. Likewise, hiOK must be monotone for smaller
+// upper bounds. Subtrees that cannot contain a match are not visited.
+//
+// On return, c points to the same node as when search was called.
+func (t *Tree[K, V]) search(c *stree.Cursor[entry[K, V]], loOK, hiOK func(K) bool, yield func(Interval[K], V) bool) bool {
+	if !c.Valid() {
+		return true // empty tree
+	}
+	e := c.Key()
+	if !hiOK(e.top) {
+		return true // no interval in this subtree reaches far enough
+	}
+	if c.HasLeft() {
+		ok := t.search(c.Left(), loOK, hiOK, yield)
+		c.Up()
+		if !ok {
+			return false
+		}
+	}
+	if !loOK(e.iv.Lo) {
+		return true // c and all its successors start too late
+	}
+	if hiOK(e.iv.Hi) && !yield(e.iv, e.val) {
+		return false
+	}
+	if c.HasRight() {
+		ok := t.search(c.Right(), loOK, hiOK, yield)
+		c.Up()
+		return ok
+	}
+	return true
+}
+
+// compareInterval compares intervals by their lower bounds, then by their
+// upper bounds.
+func (t *Tree[K, V]) compareInterval(a, b Interval[K]) int {
+	if c := t.compare(a.Lo, b.Lo); c != 0 {
+		return c
+	}
+	return t.compare(a.Hi, b.Hi)
+}
+
+// compareEntry orders entries by interval, then by sequence number.
+func (t *Tree[K, V]) compareEntry(a, b entry[K, V]) int {
+	if c := t.compareInterval(a.iv, b.iv); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.seq, b.seq)
+}
+
+// fix recomputes the top of x from its own interval and its children.
+// It is the augmentation function of the underlying tree.
+func (t *Tree[K, V]) fix(x, left, right *entry[K, V]) {
+	x.top = x.iv.Hi
+	if left != nil && t.compare(left.top, x.top) > 0 {
+		x.top = left.top
+	}
+	if right != nil && t.compare(right.top, x.top) > 0 {
+		x.top = right.top
+	}
+}
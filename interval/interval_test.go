@@ -0,0 +1,186 @@
+package interval_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/creachadair/mds/interval"
+	"github.com/creachadair/mds/mtest"
+	gocmp "github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+type entry struct {
+	Lo, Hi int
+	V      string
+}
+
+func collect(seq func(func(interval.Interval[int], string) bool)) []entry {
+	var out []entry
+	for iv, v := range seq {
+		out = append(out, entry{iv.Lo, iv.Hi, v})
+	}
+	return out
+}
+
+func TestTree(t *testing.T) {
+	tree := interval.New[int, string]()
+	if !tree.IsEmpty() || tree.Len() != 0 {
+		t.Errorf("New tree: got len %d, want empty", tree.Len())
+	}
+	tree.Add(1, 5, "a")
+	tree.Add(3, 9, "b")
+	tree.Add(6, 12, "c")
+	tree.Add(3, 9, "d")
+	tree.Add(7, 7, "e") // empty
+
+	if got := tree.Len(); got != 5 {
+		t.Errorf("Len: got %d, want 5", got)
+	}
+	if diff := gocmp.Diff(collect(tree.Inorder), []entry{
+		{1, 5, "a"}, {3, 9, "b"}, {3, 9, "d"}, {6, 12, "c"}, {7, 7, "e"},
+	}); diff != "" {
+		t.Errorf("Inorder (-got, +want):\n%s", diff)
+	}
+
+	tests := []struct {
+		p    int
+		want []entry
+	}{
+		{0, nil},
+		{1, []entry{{1, 5, "a"}}},
+		{4, []entry{{1, 5, "a"}, {3, 9, "b"}, {3, 9, "d"}}},
+		{5, []entry{{3, 9, "b"}, {3, 9, "d"}}},
+		{7, []entry{{3, 9, "b"}, {3, 9, "d"}, {6, 12, "c"}}},
+		{11, []entry{{6, 12, "c"}}},
+		{12, nil},
+	}
+	for _, tc := range tests {
+		if diff := gocmp.Diff(collect(tree.Stab(tc.p)), tc.want); diff != "" {
+			t.Errorf("Stab(%d) (-got, +want):\n%s", tc.p, diff)
+		}
+	}
+
+	otests := []struct {
+		lo, hi int
+		want   []entry
+	}{
+		{0, 1, nil},
+		{0, 2, []entry{{1, 5, "a"}}},
+		{5, 6, []entry{{3, 9, "b"}, {3, 9, "d"}}},
+		{7, 8, []entry{{3, 9, "b"}, {3, 9, "d"}, {6, 12, "c"}}},
+		{9, 20, []entry{{6, 12, "c"}}},
+		{4, 4, nil}, // empty query
+		{12, 20, nil},
+	}
+	for _, tc := range otests {
+		if diff := gocmp.Diff(collect(tree.Overlaps(tc.lo, tc.hi)), tc.want); diff != "" {
+			t.Errorf("Overlaps(%d, %d) (-got, +want):\n%s", tc.lo, tc.hi, diff)
+		}
+	}
+
+	if v, ok := tree.Remove(3, 9); !ok || (v != "b" && v != "d") {
+		t.Errorf("Remove(3, 9): got (%q, %v), want b or d", v, ok)
+	}
+	if v, ok := tree.Remove(3, 8); ok {
+		t.Errorf("Remove(3, 8): got (%q, true), want false", v)
+	}
+	if got := len(collect(tree.Stab(4))); got != 2 {
+		t.Errorf("Stab(4) after Remove: got %d entries, want 2", got)
+	}
+
+	tree.Clear()
+	if !tree.IsEmpty() || collect(tree.Inorder) != nil {
+		t.Errorf("After Clear: got %v, want empty", collect(tree.Inorder))
+	}
+
+	mtest.MustPanic(t, func() { tree.Add(5, 4, "bad") })
+	mtest.MustPanic(t, func() { interval.NewFunc[int, string](nil) })
+}
+
+func TestStop(t *testing.T) {
+	tree := interval.New[int, string]()
+	for i := range 10 {
+		tree.Add(i, i+5, "x")
+	}
+	var n int
+	for range tree.Stab(6) {
+		n++
+		if n == 2 {
+			break
+		}
+	}
+	if n != 2 {
+		t.Errorf("Stab: visited %d entries, want 2", n)
+	}
+}
+
+func TestRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	tree := interval.New[int, string]()
+	var model []entry
+
+	// check verifies that the results of a query agree with a linear scan of
+	// the model using match.
+	check := func(name string, got []entry, match func(e entry) bool) {
+		t.Helper()
+		var want []entry
+		for _, e := range model {
+			if match(e) {
+				want = append(want, e)
+			}
+		}
+		// The model does not keep the order of duplicate intervals.
+		opt := cmpopts.SortSlices(func(a, b entry) bool {
+			return a.Lo < b.Lo || (a.Lo == b.Lo && (a.Hi < b.Hi || (a.Hi == b.Hi && a.V < b.V)))
+		})
+		if diff := gocmp.Diff(got, want, opt, cmpopts.EquateEmpty()); diff != "" {
+			t.Fatalf("%s (-got, +want):\n%s", name, diff)
+		}
+		if !slices.IsSortedFunc(got, func(a, b entry) int {
+			if a.Lo != b.Lo {
+				return a.Lo - b.Lo
+			}
+			return a.Hi - b.Hi
+		}) {
+			t.Fatalf("%s: result is not in order: %v", name, got)
+		}
+	}
+
+	for i := range 3000 {
+		lo := rng.IntN(200)
+		hi := lo + rng.IntN(30)
+		if rng.IntN(3) == 0 && len(model) != 0 {
+			e := model[rng.IntN(len(model))]
+			v, ok := tree.Remove(e.Lo, e.Hi)
+			if !ok {
+				t.Fatalf("Remove(%d, %d): not found", e.Lo, e.Hi)
+			}
+			j := slices.Index(model, entry{e.Lo, e.Hi, v})
+			if j < 0 {
+				t.Fatalf("Remove(%d, %d): unexpected value %q", e.Lo, e.Hi, v)
+			}
+			model = slices.Delete(model, j, j+1)
+		} else {
+			e := entry{lo, hi, string(rune('a' + i%26))}
+			tree.Add(e.Lo, e.Hi, e.V)
+			model = append(model, e)
+		}
+		if tree.Len() != len(model) {
+			t.Fatalf("Len: got %d, want %d", tree.Len(), len(model))
+		}
+		if i%10 != 0 {
+			continue
+		}
+
+		p := rng.IntN(240)
+		check("Stab", collect(tree.Stab(p)), func(e entry) bool { return e.Lo <= p && p < e.Hi })
+
+		qlo := rng.IntN(240)
+		qhi := qlo + rng.IntN(20)
+		check("Overlaps", collect(tree.Overlaps(qlo, qhi)), func(e entry) bool {
+			return e.Lo < e.Hi && qlo < qhi && e.Lo < qhi && qlo < e.Hi
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package mapset
+
+// A Canon represents a set of values of a type S that need not be comparable,
+// such as slices or structs containing slices. Each value is identified by a
+// comparable canonical key of type K, computed by a function provided when
+// the set is constructed. Values with the same key are considered equal, and
+// the set retains the first such value added.
+//
+// For example, a set of byte slices identified by their contents:
+//
+//	s := mapset.NewCanon(func(b []byte) string { return string(b) })
+//
+// A zero Canon is not ready for use; construct one with [NewCanon]. A Canon
+// is not safe for concurrent use without external synchronization.
+type Canon[S any, K comparable] struct {
+	key func(S) K
+	m   map[K]S
+}
+
+// NewCanon constructs a set of the specified items, identified by the keys
+// computed by key. The key function must be deterministic, and must not
+// depend on the contents of the set. The result is never nil, even if no
+// items are provided.
+func NewCanon[S any, K comparable](key func(S) K, items ...S) *Canon[S, K] {
+	c := &Canon[S, K]{key: key, m: make(map[K]S, len(items))}
+	return c.Add(items...)
+}
+
+// IsEmpty reports whether c is empty.
+func (c *Canon[S, K]) IsEmpty() bool { return len(c.m) == 0 }
+
+// Len reports the number of elements in c.
+func (c *Canon[S, K]) Len() int { return len(c.m) }
+
+// Clear removes all elements from c and returns c.
+func (c *Canon[S, K]) Clear() *Canon[S, K] { clear(c.m); return c }
+
+// Has reports whether a value with the same key as v is present in c.
+func (c *Canon[S, K]) Has(v S) bool { _, ok := c.m[c.key(v)]; return ok }
+
+// Get reports whether a value with the same key as v is present in c, and if
+// so returns the stored value.
+func (c *Canon[S, K]) Get(v S) (S, bool) { s, ok := c.m[c.key(v)]; return s, ok }
+
+// Add adds the specified items to c and returns c. Items whose keys are
+// already present in c do not replace the existing values.
+func (c *Canon[S, K]) Add(items ...S) *Canon[S, K] {
+	for _, item := range items {
+		if k := c.key(item); !hasKey(c.m, k) {
+			c.m[k] = item
+		}
+	}
+	return c
+}
+
+// Remove removes the values with the same keys as the specified items from c
+// and returns c.
+func (c *Canon[S, K]) Remove(items ...S) *Canon[S, K] {
+	for _, item := range items {
+		delete(c.m, c.key(item))
+	}
+	return c
+}
+
+// Equals reports whether c and d contain values with exactly the same keys.
+func (c *Canon[S, K]) Equals(d *Canon[S, K]) bool {
+	if len(c.m) != len(d.m) {
+		return false
+	}
+	for k := range c.m {
+		if !hasKey(d.m, k) {
+			return false
+		}
+	}
+	return true
+}
+
+// Each is a range function that calls f with each value in c, in arbitrary
+// order. If f returns false, Each returns immediately.
+func (c *Canon[S, K]) Each(f func(S) bool) {
+	for _, v := range c.m {
+		if !f(v) {
+			return
+		}
+	}
+}
+
+// Keys returns a set of the canonical keys of the values in c.
+// The result is never nil, even if c is empty.
+func (c *Canon[S, K]) Keys() Set[K] { return Keys(c.m) }
+
+// Slice returns a slice of the values in c, in arbitrary order.
+// If c is empty, Slice returns nil.
+func (c *Canon[S, K]) Slice() []S {
+	if len(c.m) == 0 {
+		return nil
+	}
+	out := make([]S, 0, len(c.m))
+	for _, v := range c.m {
+		out = append(out, v)
+	}
+	return out
+}
+
+func hasKey[K comparable, V any](m map[K]V, k K) bool { _, ok := m[k]; return ok }
@@ -5,8 +5,10 @@
 package mapset
 
 import (
+	"cmp"
 	"iter"
 	"maps"
+	"math/rand/v2"
 )
 
 // A Set represents a set of distinct values. It is implemented via the
@@ -96,6 +98,67 @@ func (s Set[T]) RemoveAll(t Set[T]) Set[T] {
 	return s
 }
 
+// AddSeq adds all the values of it to s and returns s.
+func (s *Set[T]) AddSeq(it iter.Seq[T]) Set[T] {
+	if *s == nil {
+		*s = make(Set[T])
+	}
+	for item := range it {
+		(*s)[item] = struct{}{}
+	}
+	return *s
+}
+
+// RemoveSeq removes all the values of it from s and returns s.  It stops
+// consuming it once s becomes empty.
+func (s Set[T]) RemoveSeq(it iter.Seq[T]) Set[T] {
+	for item := range it {
+		if len(s) == 0 {
+			break
+		}
+		delete(s, item)
+	}
+	return s
+}
+
+// RemoveMatching removes from s all the elements for which f returns true,
+// and returns s.
+func (s Set[T]) RemoveMatching(f func(T) bool) Set[T] {
+	for item := range s {
+		if f(item) {
+			delete(s, item)
+		}
+	}
+	return s
+}
+
+// IntersectSeq returns an iterator over the values of it that are elements of
+// s, in the order they are delivered by it. Unlike Intersect, this does not
+// construct a set from it, so a large stream can be filtered against s in
+// constant space. If it repeats a value, each occurrence is delivered.
+func (s Set[T]) IntersectSeq(it iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range it {
+			if s.Has(v) && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// ExceptSeq returns an iterator over the values of it that are not elements
+// of s, in the order they are delivered by it. If it repeats a value, each
+// occurrence is delivered.
+func (s Set[T]) ExceptSeq(it iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range it {
+			if !s.Has(v) && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
 // Pop removes and returns an arbitrary element of s, if s is non-empty.
 // If s is empty, it returns a zero value.
 func (s Set[T]) Pop() T {
@@ -200,6 +263,56 @@ func (s Set[T]) Slice() []T {
 	return s.Append(make([]T, 0, len(s)))
 }
 
+// SampleN returns a new set containing a uniform random sample of n distinct
+// elements of s, chosen using rng. If n ≥ len(s), the result contains all the
+// elements of s; if n ≤ 0 the result is empty. The result is never nil, and s
+// is not modified.
+func (s Set[T]) SampleN(rng *rand.Rand, n int) Set[T] {
+	if n >= len(s) {
+		return s.Clone()
+	} else if n <= 0 {
+		return make(Set[T])
+	}
+
+	// Reservoir sampling: Fill the reservoir with the first n elements, then
+	// replace the kth element with probability n/k.
+	buf := make([]T, 0, n)
+	k := 0
+	for item := range s {
+		k++
+		if len(buf) < n {
+			buf = append(buf, item)
+		} else if j := rng.IntN(k); j < n {
+			buf[j] = item
+		}
+	}
+	return New(buf...)
+}
+
+// SplitN partitions the elements of s into n disjoint new sets whose union is
+// s, and whose sizes differ by at most one. The assignment of elements to
+// sets is arbitrary. If n > len(s), some of the results will be empty, but
+// none of them is nil. The set s is not modified.
+//
+// SplitN will panic if n < 0. If n == 0, SplitN returns nil.
+func SplitN[T comparable](s Set[T], n int) []Set[T] {
+	if n < 0 {
+		panic("n out of range")
+	} else if n == 0 {
+		return nil
+	}
+	out := make([]Set[T], n)
+	for i := range out {
+		out[i] = make(Set[T], (len(s)+n-1)/n)
+	}
+	i := 0
+	for item := range s {
+		out[i][item] = struct{}{}
+		i = (i + 1) % n
+	}
+	return out
+}
+
 // Intersect constructs a new set containing the intersection of the specified
 // sets.  The result is never nil, even if the given sets are empty.
 func Intersect[T comparable](ss ...Set[T]) Set[T] {
@@ -227,6 +340,59 @@ nextElt:
 	return out
 }
 
+// Fold combines the elements of s into a single value, by calling f on an
+// accumulator and each element of s. The accumulator starts at init and is
+// replaced by each result of f. If s is empty, Fold returns init.
+//
+// The elements of s are visited in arbitrary order, so the result is only
+// well-defined if f does not depend on the order of its arguments.
+func Fold[T comparable, U any](s Set[T], init U, f func(U, T) U) U {
+	acc := init
+	for v := range s {
+		acc = f(acc, v)
+	}
+	return acc
+}
+
+// number is the set of built-in numeric types and types derived from them.
+type number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Sum returns the sum of the elements of s. If s is empty, Sum returns 0.
+func Sum[T number](s Set[T]) T {
+	var sum T
+	for v := range s {
+		sum += v
+	}
+	return sum
+}
+
+// Min reports whether s is non-empty, and if so returns its minimum element.
+// If s is empty, Min returns a zero value and false.
+func Min[T cmp.Ordered](s Set[T]) (T, bool) {
+	return extremum(s, func(a, b T) T { return min(a, b) })
+}
+
+// Max reports whether s is non-empty, and if so returns its maximum element.
+// If s is empty, Max returns a zero value and false.
+func Max[T cmp.Ordered](s Set[T]) (T, bool) {
+	return extremum(s, func(a, b T) T { return max(a, b) })
+}
+
+func extremum[T comparable](s Set[T], pick func(a, b T) T) (out T, ok bool) {
+	for v := range s {
+		if !ok {
+			out, ok = v, true
+		} else {
+			out = pick(out, v)
+		}
+	}
+	return out, ok
+}
+
 // Range constructs a new Set containing the values of it.
 func Range[T comparable](it iter.Seq[T]) Set[T] {
 	out := make(Set[T])
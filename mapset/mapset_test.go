@@ -2,6 +2,7 @@ package mapset_test
 
 import (
 	"iter"
+	"math/rand/v2"
 	"slices"
 	"strings"
 	"testing"
@@ -370,3 +371,203 @@ func TestRange(t *testing.T) {
 		}
 	}
 }
+
+func TestFold(t *testing.T) {
+	empty := mapset.New[int]()
+	s := mapset.New(5, 3, 11, -2, 8)
+
+	if got := mapset.Fold(s, 1, func(p, v int) int { return p * v }); got != -2640 {
+		t.Errorf("Fold product: got %d, want -2640", got)
+	}
+	if got := mapset.Fold(empty, "init", func(string, int) string { return "bad" }); got != "init" {
+		t.Errorf("Fold empty: got %q, want init", got)
+	}
+
+	if got := mapset.Sum(s); got != 25 {
+		t.Errorf("Sum: got %d, want 25", got)
+	}
+	if got := mapset.Sum(mapset.New(0.5, 0.25)); got != 0.75 {
+		t.Errorf("Sum: got %v, want 0.75", got)
+	}
+	if got := mapset.Sum(empty); got != 0 {
+		t.Errorf("Sum empty: got %d, want 0", got)
+	}
+
+	if got, ok := mapset.Min(s); !ok || got != -2 {
+		t.Errorf("Min: got (%d, %v), want (-2, true)", got, ok)
+	}
+	if got, ok := mapset.Max(s); !ok || got != 11 {
+		t.Errorf("Max: got (%d, %v), want (11, true)", got, ok)
+	}
+	if got, ok := mapset.Max(mapset.New("pear", "apple", "plum")); !ok || got != "plum" {
+		t.Errorf("Max: got (%q, %v), want (plum, true)", got, ok)
+	}
+	if got, ok := mapset.Min(empty); ok {
+		t.Errorf("Min empty: got (%d, true), want (0, false)", got)
+	}
+	if got, ok := mapset.Max(empty); ok {
+		t.Errorf("Max empty: got (%d, true), want (0, false)", got)
+	}
+}
+
+func TestSeqOps(t *testing.T) {
+	vals := func(items ...int) iter.Seq[int] { return slices.Values(items) }
+
+	var s mapset.Set[int]
+	check(t, s.AddSeq(vals(1, 2, 3, 2, 5, 8)), 1, 2, 3, 5, 8)
+	check(t, s.AddSeq(vals()), 1, 2, 3, 5, 8)
+
+	input := vals(0, 1, 2, 4, 5, 1, 9)
+	got := slices.Collect(s.IntersectSeq(input))
+	if diff := cmp.Diff(got, []int{1, 2, 5, 1}); diff != "" {
+		t.Errorf("IntersectSeq (-got, +want):\n%s", diff)
+	}
+	got = slices.Collect(s.ExceptSeq(input))
+	if diff := cmp.Diff(got, []int{0, 4, 9}); diff != "" {
+		t.Errorf("ExceptSeq (-got, +want):\n%s", diff)
+	}
+	for v := range s.IntersectSeq(input) {
+		if v != 1 {
+			t.Errorf("IntersectSeq: got %d, want 1", v)
+		}
+		break // check early exit
+	}
+
+	check(t, s.RemoveSeq(vals(2, 4, 8)), 1, 3, 5)
+	check(t, s.RemoveMatching(func(v int) bool { return v > 2 }), 1)
+	check(t, s.RemoveMatching(func(int) bool { return true }))
+
+	// RemoveSeq stops consuming its input when the set becomes empty.
+	var n int
+	mapset.New(1, 2).RemoveSeq(func(yield func(int) bool) {
+		for i := 1; yield(i); i++ {
+			n++
+		}
+	})
+	if n != 2 {
+		t.Errorf("RemoveSeq consumed %d values, want 2", n)
+	}
+}
+
+func TestSampleN(t *testing.T) {
+	s := mapset.New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	check(t, s.SampleN(rng, 0))
+	check(t, s.SampleN(rng, -1))
+	check(t, s.SampleN(rng, 10), s.Slice()...)
+	check(t, s.SampleN(rng, 25), s.Slice()...)
+
+	for n := 1; n < s.Len(); n++ {
+		got := s.SampleN(rng, n)
+		if got.Len() != n {
+			t.Errorf("SampleN(%d): got %d elements, want %d", n, got.Len(), n)
+		}
+		if !got.IsSubset(s) {
+			t.Errorf("SampleN(%d): got %v, not a subset of %v", n, got, s)
+		}
+	}
+	if s.Len() != 10 {
+		t.Errorf("SampleN modified its input: %v", s)
+	}
+
+	// Every element should be chosen at least once in many trials.
+	seen := mapset.New[int]()
+	for range 200 {
+		seen.AddAll(s.SampleN(rng, 2))
+	}
+	if !seen.Equals(s) {
+		t.Errorf("SampleN: got samples %v, want all of %v", seen, s)
+	}
+}
+
+func TestSplitN(t *testing.T) {
+	s := mapset.New(strings.Fields("a b c d e f g h i j k")...)
+
+	if got := mapset.SplitN(s, 0); got != nil {
+		t.Errorf("SplitN(s, 0): got %v, want nil", got)
+	}
+	for n := 1; n <= 15; n++ {
+		parts := mapset.SplitN(s, n)
+		if len(parts) != n {
+			t.Errorf("SplitN(s, %d): got %d parts, want %d", n, len(parts), n)
+		}
+		var all mapset.Set[string]
+		lo, hi := s.Len(), 0
+		for _, p := range parts {
+			if p == nil {
+				t.Errorf("SplitN(s, %d): got nil part", n)
+			}
+			if all.Intersects(p) {
+				t.Errorf("SplitN(s, %d): part %v overlaps %v", n, p, all)
+			}
+			all.AddAll(p)
+			lo, hi = min(lo, p.Len()), max(hi, p.Len())
+		}
+		if !all.Equals(s) {
+			t.Errorf("SplitN(s, %d): union is %v, want %v", n, all, s)
+		}
+		if hi-lo > 1 {
+			t.Errorf("SplitN(s, %d): part sizes range from %d to %d", n, lo, hi)
+		}
+	}
+	if s.Len() != 11 {
+		t.Errorf("SplitN modified its input: %v", s)
+	}
+}
+
+func TestCanon(t *testing.T) {
+	key := func(b []byte) string { return string(b) }
+	sorted := func(c *mapset.Canon[[]byte, string]) []string {
+		keys := c.Keys().Slice()
+		slices.Sort(keys)
+		return keys
+	}
+
+	a1 := []byte("alpha")
+	s := mapset.NewCanon(key, a1, []byte("bravo"), []byte("alpha"))
+	if got := s.Len(); got != 2 {
+		t.Errorf("Len: got %d, want 2", got)
+	}
+	if diff := cmp.Diff(sorted(s), []string{"alpha", "bravo"}); diff != "" {
+		t.Errorf("Keys (-got, +want):\n%s", diff)
+	}
+
+	// The first value added for each key is retained.
+	if got, ok := s.Get([]byte("alpha")); !ok || &got[0] != &a1[0] {
+		t.Errorf("Get(alpha): got (%q, %v), want original value", got, ok)
+	}
+	if !s.Has([]byte("bravo")) || s.Has([]byte("charlie")) {
+		t.Errorf("Has: wrong result for %q", sorted(s))
+	}
+
+	s.Add([]byte("charlie"), []byte("delta")).Remove([]byte("bravo"), []byte("echo"))
+	if diff := cmp.Diff(sorted(s), []string{"alpha", "charlie", "delta"}); diff != "" {
+		t.Errorf("Keys (-got, +want):\n%s", diff)
+	}
+
+	var got []string
+	for v := range s.Each {
+		got = append(got, string(v))
+	}
+	slices.Sort(got)
+	if diff := cmp.Diff(got, []string{"alpha", "charlie", "delta"}); diff != "" {
+		t.Errorf("Each (-got, +want):\n%s", diff)
+	}
+	if got := s.Slice(); len(got) != 3 {
+		t.Errorf("Slice: got %q, want 3 values", got)
+	}
+
+	other := mapset.NewCanon(key, []byte("delta"), []byte("charlie"), []byte("alpha"))
+	if !s.Equals(other) {
+		t.Errorf("Equals(%q, %q): got false, want true", sorted(s), sorted(other))
+	}
+	other.Remove([]byte("alpha"))
+	if s.Equals(other) {
+		t.Errorf("Equals(%q, %q): got true, want false", sorted(s), sorted(other))
+	}
+
+	if !s.Clear().IsEmpty() || s.Slice() != nil {
+		t.Errorf("After Clear: got %q, want empty", s.Slice())
+	}
+}
@@ -0,0 +1,241 @@
+package mcodec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+
+	"github.com/creachadair/mds/mapset"
+	"github.com/creachadair/mds/omap"
+	"github.com/creachadair/mds/queue"
+)
+
+// WriteJSONSeq writes the values of seq to w as a JSON array, followed by a
+// newline. The values are encoded one at a time as they are produced.
+func WriteJSONSeq[T any](w io.Writer, seq iter.Seq[T]) error {
+	sep := "["
+	for v := range seq {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		} else if _, err := io.WriteString(w, sep); err != nil {
+			return err
+		} else if _, err := w.Write(data); err != nil {
+			return err
+		}
+		sep = ","
+	}
+	end := "]\n"
+	if sep == "[" {
+		end = "[]\n" // empty
+	}
+	_, err := io.WriteString(w, end)
+	return err
+}
+
+// WriteJSONSeq2 writes the key-value pairs of seq to w as a JSON array of
+// two-element [key, value] arrays, followed by a newline. The pairs are
+// encoded one at a time as they are produced.
+func WriteJSONSeq2[K, V any](w io.Writer, seq iter.Seq2[K, V]) error {
+	sep := "["
+	for k, v := range seq {
+		kdata, err := json.Marshal(k)
+		if err != nil {
+			return err
+		}
+		vdata, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s[%s,%s]", sep, kdata, vdata); err != nil {
+			return err
+		}
+		sep = ","
+	}
+	end := "]\n"
+	if sep == "[" {
+		end = "[]\n" // empty
+	}
+	_, err := io.WriteString(w, end)
+	return err
+}
+
+// A JSONDecoder reads JSON arrays of values from an [io.Reader].
+//
+// The streaming functions [ReadJSONSeq] and [ReadJSONSeq2] record the first
+// error they encounter in the JSONDecoder, which can be recovered by calling
+// Err after the iteration is complete. Once an error has occurred, all
+// further reads from the JSONDecoder report the same error.
+type JSONDecoder struct {
+	dec *json.Decoder
+	err error
+}
+
+// NewJSONDecoder constructs a JSONDecoder that reads from r.
+// The JSONDecoder may buffer data from r beyond the values it has read.
+func NewJSONDecoder(r io.Reader) *JSONDecoder {
+	return &JSONDecoder{dec: json.NewDecoder(r)}
+}
+
+// Err reports the first error encountered while reading from d, or nil.
+// If the input ended before the beginning of an array, Err reports io.EOF.
+func (d *JSONDecoder) Err() error { return d.err }
+
+// fail records err as the error for d if d does not already have one, and
+// returns the recorded error.
+func (d *JSONDecoder) fail(err error) error {
+	if d.err == nil {
+		d.err = err
+	}
+	return d.err
+}
+
+// begin reads the start of an array from d. It reports false if the array is
+// empty (including JSON null) or if an error occurred.
+func (d *JSONDecoder) begin() bool {
+	if d.err != nil {
+		return false
+	}
+	tok, err := d.dec.Token()
+	if err != nil {
+		d.fail(err)
+		return false
+	} else if tok == nil {
+		return false // null
+	} else if tok != json.Delim('[') {
+		d.fail(fmt.Errorf("got %v, want start of array", tok))
+		return false
+	}
+	return true
+}
+
+// expect reads a delimiter token from d and reports an error if it is not
+// want. The caller is expected to be within an array.
+func (d *JSONDecoder) expect(want json.Delim) bool {
+	if d.err != nil {
+		return false
+	}
+	tok, err := d.dec.Token()
+	if err != nil {
+		d.fail(noEOF(err))
+		return false
+	} else if tok != want {
+		d.fail(fmt.Errorf("got %v, want %v", tok, want))
+		return false
+	}
+	return true
+}
+
+// decode decodes the next value in the current array of d into v.
+func (d *JSONDecoder) decode(v any) bool {
+	if d.err != nil {
+		return false
+	} else if err := d.dec.Decode(v); err != nil {
+		d.fail(noEOF(err))
+		return false
+	}
+	return true
+}
+
+// finish discards any remaining values of the current array of d, and reads
+// the end of the array.
+func (d *JSONDecoder) finish() {
+	for d.err == nil && d.dec.More() {
+		var skip json.RawMessage
+		d.decode(&skip)
+	}
+	d.expect(']')
+}
+
+// ReadJSONSeq returns an iterator over the values of a JSON array read from
+// d. A JSON null is treated as an empty array. If an error occurs, the
+// iteration stops and the error is recorded in d (see [JSONDecoder.Err]). If
+// the caller stops the iteration early, the remaining values of the array are
+// read and discarded, so that d is ready to read whatever follows the array.
+func ReadJSONSeq[T any](d *JSONDecoder) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if !d.begin() {
+			return
+		}
+		defer d.finish()
+		for d.dec.More() {
+			var v T
+			if !d.decode(&v) || !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// ReadJSONSeq2 returns an iterator over the key-value pairs of a JSON array
+// of two-element [key, value] arrays read from d. It handles errors and early
+// termination in the same manner as [ReadJSONSeq].
+func ReadJSONSeq2[K, V any](d *JSONDecoder) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if !d.begin() {
+			return
+		}
+		defer d.finish()
+		for d.dec.More() {
+			var k K
+			var v V
+			if !d.expect('[') || !d.decode(&k) || !d.decode(&v) || !d.expect(']') {
+				return
+			} else if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// EncodeSetJSON writes the elements of s to w as a JSON array.
+func EncodeSetJSON[T comparable](w io.Writer, s mapset.Set[T]) error {
+	return WriteJSONSeq(w, maps.Keys(s))
+}
+
+// DecodeSetJSON reads a JSON array of values from d, and returns a set of the
+// values.
+func DecodeSetJSON[T comparable](d *JSONDecoder) (mapset.Set[T], error) {
+	s := mapset.New[T]()
+	s.AddSeq(ReadJSONSeq[T](d))
+	if err := d.Err(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// EncodeMapJSON writes the key-value pairs of m to w in order by key, as a
+// JSON array of two-element [key, value] arrays. This is the same format used
+// by the MarshalJSON method of omap.Map, but written incrementally.
+func EncodeMapJSON[K, V any](w io.Writer, m omap.Map[K, V]) error {
+	return WriteJSONSeq2(w, mapPairs(m))
+}
+
+// DecodeMapJSON reads a JSON array of two-element [key, value] arrays from d,
+// and adds the key-value pairs to m. The caller must construct m, which
+// determines the order of its keys. If the array contains duplicate keys, the
+// last value for each key is retained. If an error occurs, m is not modified.
+func DecodeMapJSON[K, V any](d *JSONDecoder, m omap.Map[K, V]) error {
+	return setPairs(m, ReadJSONSeq2[K, V](d), d.Err)
+}
+
+// EncodeQueueJSON writes the values of q to w as a JSON array, in order from
+// oldest to newest.
+func EncodeQueueJSON[T any](w io.Writer, q *queue.Queue[T]) error {
+	return WriteJSONSeq(w, q.Each)
+}
+
+// DecodeQueueJSON reads a JSON array of values from d, and returns a queue of
+// the values, with the first value read at the front.
+func DecodeQueueJSON[T any](d *JSONDecoder) (*queue.Queue[T], error) {
+	q := queue.New[T]()
+	for v := range ReadJSONSeq[T](d) {
+		q.Add(v)
+	}
+	if err := d.Err(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
@@ -0,0 +1,452 @@
+// Package mcodec implements binary and JSON encodings for the container types
+// in this module, so that their contents can be persisted and restored.
+//
+// # Binary Encoding
+//
+// The binary encoding is a sequence of length-prefixed records. Each record
+// is a uvarint length followed by that many bytes of data. A sequence of
+// values is encoded as a uvarint count followed by one record for each value.
+// A sequence of key-value pairs is encoded in the same way, except that each
+// pair is stored as two records, the key followed by the value.
+//
+// Values are converted to and from record data using a [Codec]. Codecs for
+// some common types are provided (e.g., [String], [Int64]).
+//
+// Use an [Encoder] to write a stream of records, and a [Decoder] to read them.
+// Containers are encoded as sequences:
+//
+//	e := mcodec.NewEncoder(w)
+//	if err := mcodec.EncodeQueue(e, q, mcodec.String); err != nil {
+//	   log.Fatalf("Encode: %v", err)
+//	}
+//
+// To decode a sequence without constructing a container, use [ReadSeq] or
+// [ReadSeq2] and check [Decoder.Err] after the iteration:
+//
+//	d := mcodec.NewDecoder(r)
+//	for v := range mcodec.ReadSeq(d, mcodec.String) {
+//	   process(v)
+//	}
+//	if err := d.Err(); err != nil {
+//	   log.Fatalf("Decode: %v", err)
+//	}
+//
+// # JSON Encoding
+//
+// A sequence of values is encoded in JSON as an array of the values, and a
+// sequence of key-value pairs is encoded as an array of two-element arrays
+// holding the key and the value. Values are converted using the
+// [encoding/json] package. Use a [JSONDecoder] to stream values from a JSON
+// array with [ReadJSONSeq] and [ReadJSONSeq2].
+//
+// # Order
+//
+// The elements of an omap.Map are encoded in order by key, and those of a
+// queue.Queue in order from oldest to newest. The elements of a mapset.Set are
+// encoded in an unspecified order.
+package mcodec
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/creachadair/mds/mapset"
+	"github.com/creachadair/mds/omap"
+	"github.com/creachadair/mds/queue"
+)
+
+// MaxRecordLen is the maximum length in bytes of a record accepted by a
+// [Decoder]. Records longer than this are reported as errors.
+const MaxRecordLen = 1 << 30
+
+// A Codec converts values of type T to and from binary record data.
+type Codec[T any] struct {
+	// Append appends the binary encoding of v to buf and returns the result.
+	Append func(buf []byte, v T) []byte
+
+	// Parse decodes a value from its binary encoding in data.  Parse must not
+	// retain data after it returns.
+	Parse func(data []byte) (T, error)
+}
+
+var (
+	// String is a Codec for strings, encoded as their bytes.
+	String = Codec[string]{
+		Append: func(buf []byte, s string) []byte { return append(buf, s...) },
+		Parse:  func(data []byte) (string, error) { return string(data), nil },
+	}
+
+	// Bytes is a Codec for byte slices, encoded as themselves.
+	Bytes = Codec[[]byte]{
+		Append: func(buf, v []byte) []byte { return append(buf, v...) },
+		Parse:  func(data []byte) ([]byte, error) { return bytes.Clone(data), nil },
+	}
+
+	// Int64 is a Codec for signed integers, encoded as varints.
+	Int64 = Codec[int64]{
+		Append: binary.AppendVarint,
+		Parse: func(data []byte) (int64, error) {
+			v, n := binary.Varint(data)
+			if n <= 0 || n != len(data) {
+				return 0, errors.New("invalid varint")
+			}
+			return v, nil
+		},
+	}
+
+	// Uint64 is a Codec for unsigned integers, encoded as uvarints.
+	Uint64 = Codec[uint64]{
+		Append: binary.AppendUvarint,
+		Parse: func(data []byte) (uint64, error) {
+			v, n := binary.Uvarint(data)
+			if n <= 0 || n != len(data) {
+				return 0, errors.New("invalid uvarint")
+			}
+			return v, nil
+		},
+	}
+)
+
+// An Encoder writes length-prefixed binary records to an [io.Writer].
+type Encoder struct {
+	w    io.Writer
+	buf  []byte // record framing
+	data []byte // value encoding
+}
+
+// NewEncoder constructs an Encoder that writes records to w.
+// The Encoder does not buffer its output.
+func NewEncoder(w io.Writer) *Encoder { return &Encoder{w: w} }
+
+// WriteRecord writes a single record containing data.
+func (e *Encoder) WriteRecord(data []byte) error {
+	e.buf = append(binary.AppendUvarint(e.buf[:0], uint64(len(data))), data...)
+	_, err := e.w.Write(e.buf)
+	return err
+}
+
+// writeCount writes the element count n at the start of a sequence.
+func (e *Encoder) writeCount(n int) error {
+	e.buf = binary.AppendUvarint(e.buf[:0], uint64(n))
+	_, err := e.w.Write(e.buf)
+	return err
+}
+
+// writeValue writes a single record containing the encoding of v.
+func writeValue[T any](e *Encoder, v T, c Codec[T]) error {
+	// Encode into a separate buffer, so the length can be written first.
+	e.data = c.Append(e.data[:0], v)
+	return e.WriteRecord(e.data)
+}
+
+// WriteSeq writes a sequence of n values from seq using c. It reports an error
+// if seq does not yield exactly n values.
+func WriteSeq[T any](e *Encoder, n int, seq iter.Seq[T], c Codec[T]) error {
+	if err := e.writeCount(n); err != nil {
+		return err
+	}
+	var nw int
+	for v := range seq {
+		if nw == n {
+			return fmt.Errorf("sequence has more than %d values", n)
+		} else if err := writeValue(e, v, c); err != nil {
+			return err
+		}
+		nw++
+	}
+	if nw != n {
+		return fmt.Errorf("sequence has %d values, want %d", nw, n)
+	}
+	return nil
+}
+
+// WriteSeq2 writes a sequence of n key-value pairs from seq using kc and vc to
+// encode the keys and values. It reports an error if seq does not yield
+// exactly n pairs.
+func WriteSeq2[K, V any](e *Encoder, n int, seq iter.Seq2[K, V], kc Codec[K], vc Codec[V]) error {
+	if err := e.writeCount(n); err != nil {
+		return err
+	}
+	var nw int
+	for k, v := range seq {
+		if nw == n {
+			return fmt.Errorf("sequence has more than %d pairs", n)
+		} else if err := writeValue(e, k, kc); err != nil {
+			return err
+		} else if err := writeValue(e, v, vc); err != nil {
+			return err
+		}
+		nw++
+	}
+	if nw != n {
+		return fmt.Errorf("sequence has %d pairs, want %d", nw, n)
+	}
+	return nil
+}
+
+// A Decoder reads length-prefixed binary records from an [io.Reader].
+//
+// The streaming functions [ReadSeq] and [ReadSeq2] record the first error
+// they encounter in the Decoder, which can be recovered by calling Err after
+// the iteration is complete. Once an error has occurred, all further reads
+// from the Decoder report the same error.
+type Decoder struct {
+	r   *bufio.Reader
+	buf []byte // record data, reused
+	err error
+}
+
+// NewDecoder constructs a Decoder that reads records from r.
+// The Decoder may buffer data from r beyond the records it has read.
+func NewDecoder(r io.Reader) *Decoder { return &Decoder{r: bufio.NewReader(r)} }
+
+// Err reports the first error encountered while reading from d, or nil.
+// If the input ended before the beginning of a sequence, Err reports io.EOF.
+func (d *Decoder) Err() error { return d.err }
+
+// ReadRecord reads and returns the data of the next record from d.
+// If there are no further records, it returns nil, io.EOF.
+func (d *Decoder) ReadRecord() ([]byte, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	data, err := d.readRecord()
+	if err != nil {
+		return nil, d.fail(err)
+	}
+	return bytes.Clone(data), nil
+}
+
+// readRecord reads the next record from d. The result is only valid until
+// the next read. It does not update d.err.
+func (d *Decoder) readRecord() ([]byte, error) {
+	n, err := d.readUvarint()
+	if err != nil {
+		return nil, err
+	} else if n > MaxRecordLen {
+		return nil, fmt.Errorf("record length %d exceeds maximum %d", n, MaxRecordLen)
+	}
+
+	// Grow the buffer as data arrive, rather than trusting the length prefix,
+	// so that a corrupt prefix cannot force a large allocation.
+	d.buf = d.buf[:0]
+	for len(d.buf) < int(n) {
+		pos := len(d.buf)
+		next := min(int(n)-pos, readChunk)
+		d.buf = slices.Grow(d.buf, next)[:pos+next]
+		nr, err := io.ReadFull(d.r, d.buf[pos:])
+		if err != nil {
+			d.buf = d.buf[:pos+nr]
+			return nil, noEOF(err)
+		}
+	}
+	return d.buf, nil
+}
+
+// readChunk is the maximum amount by which a Decoder grows its buffer before
+// reading more data.
+const readChunk = 64 << 10
+
+// readCount reads the element count at the start of a sequence.
+func (d *Decoder) readCount() (int, error) {
+	if d.err != nil {
+		return 0, d.err
+	}
+	n, err := d.readUvarint()
+	if err != nil {
+		return 0, d.fail(err)
+	} else if n > math.MaxInt {
+		return 0, d.fail(fmt.Errorf("sequence length %d out of range", n))
+	}
+	return int(n), nil
+}
+
+func (d *Decoder) readUvarint() (uint64, error) {
+	n, err := binary.ReadUvarint(d.r)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return 0, err
+	} else if err != nil && err != io.EOF {
+		return 0, fmt.Errorf("invalid length: %w", err)
+	}
+	return n, err
+}
+
+// fail records err as the error for d if d does not already have one, and
+// returns the recorded error.
+func (d *Decoder) fail(err error) error {
+	if d.err == nil {
+		d.err = err
+	}
+	return d.err
+}
+
+// readValue reads a single record from d and decodes it using c.
+func readValue[T any](d *Decoder, c Codec[T]) (T, error) {
+	var zero T
+	if d.err != nil {
+		return zero, d.err
+	}
+	data, err := d.readRecord()
+	if err != nil {
+		return zero, d.fail(noEOF(err))
+	}
+	v, err := c.Parse(data)
+	if err != nil {
+		return zero, d.fail(err)
+	}
+	return v, nil
+}
+
+// skip discards n records from d.
+func (d *Decoder) skip(n int) {
+	for range n {
+		if d.err != nil {
+			return
+		} else if _, err := d.readRecord(); err != nil {
+			d.fail(noEOF(err))
+		}
+	}
+}
+
+// ReadSeq returns an iterator over a sequence of values read from d and
+// decoded using c. If an error occurs, the iteration stops and the error is
+// recorded in d (see [Decoder.Err]). If the caller stops the iteration early,
+// the remaining values of the sequence are read and discarded, so that d is
+// ready to read whatever follows the sequence.
+func ReadSeq[T any](d *Decoder, c Codec[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		n, err := d.readCount()
+		if err != nil {
+			return
+		}
+		for i := range n {
+			v, err := readValue(d, c)
+			if err != nil {
+				return
+			} else if !yield(v) {
+				d.skip(n - i - 1)
+				return
+			}
+		}
+	}
+}
+
+// ReadSeq2 returns an iterator over a sequence of key-value pairs read from d
+// with the keys and values decoded using kc and vc. It handles errors and
+// early termination in the same manner as [ReadSeq].
+func ReadSeq2[K, V any](d *Decoder, kc Codec[K], vc Codec[V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		n, err := d.readCount()
+		if err != nil {
+			return
+		}
+		for i := range n {
+			k, err := readValue(d, kc)
+			if err != nil {
+				return
+			}
+			v, err := readValue(d, vc)
+			if err != nil {
+				return
+			} else if !yield(k, v) {
+				d.skip(2 * (n - i - 1))
+				return
+			}
+		}
+	}
+}
+
+// EncodeSet writes the elements of s to e as a sequence using c.
+func EncodeSet[T comparable](e *Encoder, s mapset.Set[T], c Codec[T]) error {
+	return WriteSeq(e, s.Len(), maps.Keys(s), c)
+}
+
+// DecodeSet reads a sequence of values from d using c, and returns a set of
+// the values.
+func DecodeSet[T comparable](d *Decoder, c Codec[T]) (mapset.Set[T], error) {
+	s := mapset.New[T]()
+	s.AddSeq(ReadSeq(d, c))
+	if err := d.Err(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// EncodeMap writes the key-value pairs of m to e as a sequence in order by
+// key, using kc and vc to encode the keys and values.
+func EncodeMap[K, V any](e *Encoder, m omap.Map[K, V], kc Codec[K], vc Codec[V]) error {
+	return WriteSeq2(e, m.Len(), mapPairs(m), kc, vc)
+}
+
+// DecodeMap reads a sequence of key-value pairs from d using kc and vc, and
+// adds them to m. The caller must construct m, which determines the order of
+// its keys. If the sequence contains duplicate keys, the last value for each
+// key is retained. If an error occurs, m is not modified.
+func DecodeMap[K, V any](d *Decoder, m omap.Map[K, V], kc Codec[K], vc Codec[V]) error {
+	return setPairs(m, ReadSeq2(d, kc, vc), d.Err)
+}
+
+// EncodeQueue writes the values of q to e as a sequence in order from oldest
+// to newest, using c.
+func EncodeQueue[T any](e *Encoder, q *queue.Queue[T], c Codec[T]) error {
+	return WriteSeq(e, q.Len(), q.Each, c)
+}
+
+// DecodeQueue reads a sequence of values from d using c, and returns a queue
+// of the values, with the first value read at the front.
+func DecodeQueue[T any](d *Decoder, c Codec[T]) (*queue.Queue[T], error) {
+	q := queue.New[T]()
+	for v := range ReadSeq(d, c) {
+		q.Add(v)
+	}
+	if err := d.Err(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// setPairs reads all the key-value pairs from seq, and then adds them to m
+// only if err reports nil, so that m is not modified by a failed decoding.
+func setPairs[K, V any](m omap.Map[K, V], seq iter.Seq2[K, V], err func() error) error {
+	var keys []K
+	var vals []V
+	for k, v := range seq {
+		keys = append(keys, k)
+		vals = append(vals, v)
+	}
+	if err := err(); err != nil {
+		return err
+	}
+	for i, k := range keys {
+		m.Set(k, vals[i])
+	}
+	return nil
+}
+
+// mapPairs returns an iterator over the key-value pairs of m in order.
+func mapPairs[K, V any](m omap.Map[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for it := m.First(); it.IsValid(); it.Next() {
+			if !yield(it.Key(), it.Value()) {
+				return
+			}
+		}
+	}
+}
+
+// noEOF converts io.EOF to io.ErrUnexpectedEOF, and returns other errors
+// unchanged. It is used when the input ends within a record or sequence.
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
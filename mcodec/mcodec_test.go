@@ -0,0 +1,343 @@
+package mcodec_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/mds/mapset"
+	"github.com/creachadair/mds/mcodec"
+	"github.com/creachadair/mds/omap"
+	"github.com/creachadair/mds/queue"
+	gocmp "github.com/google/go-cmp/cmp"
+)
+
+func mapOf(kvs ...any) omap.Map[string, int64] {
+	m := omap.New[string, int64]()
+	for i := 0; i+1 < len(kvs); i += 2 {
+		m.Set(kvs[i].(string), int64(kvs[i+1].(int)))
+	}
+	return m
+}
+
+func queueOf(vs ...string) *queue.Queue[string] {
+	q := queue.New[string]()
+	for _, v := range vs {
+		q.Add(v)
+	}
+	return q
+}
+
+func TestBinary(t *testing.T) {
+	set := mapset.New[int64](3, 1, 4, 5, 9, 2, 6)
+	m := mapOf("apple", 1, "pear", -2, "plum", 300)
+	q := queueOf("x", "", "z")
+
+	// Encode several containers into one stream, then decode them in turn.
+	var buf bytes.Buffer
+	e := mcodec.NewEncoder(&buf)
+	if err := mcodec.EncodeSet(e, set, mcodec.Int64); err != nil {
+		t.Fatalf("EncodeSet: unexpected error: %v", err)
+	}
+	if err := mcodec.EncodeMap(e, m, mcodec.String, mcodec.Int64); err != nil {
+		t.Fatalf("EncodeMap: unexpected error: %v", err)
+	}
+	if err := mcodec.EncodeQueue(e, q, mcodec.String); err != nil {
+		t.Fatalf("EncodeQueue: unexpected error: %v", err)
+	}
+	if err := mcodec.EncodeQueue(e, queue.New[string](), mcodec.String); err != nil {
+		t.Fatalf("EncodeQueue: unexpected error: %v", err)
+	}
+	data := buf.Bytes()
+	t.Logf("Encoded %d bytes", len(data))
+
+	d := mcodec.NewDecoder(bytes.NewReader(data))
+	gotSet, err := mcodec.DecodeSet(d, mcodec.Int64)
+	if err != nil {
+		t.Fatalf("DecodeSet: unexpected error: %v", err)
+	} else if !gotSet.Equals(set) {
+		t.Errorf("DecodeSet: got %v, want %v", gotSet, set)
+	}
+
+	gotMap := omap.New[string, int64]()
+	if err := mcodec.DecodeMap(d, gotMap, mcodec.String, mcodec.Int64); err != nil {
+		t.Fatalf("DecodeMap: unexpected error: %v", err)
+	} else if got, want := gotMap.String(), m.String(); got != want {
+		t.Errorf("DecodeMap: got %v, want %v", got, want)
+	}
+
+	gotQueue, err := mcodec.DecodeQueue(d, mcodec.String)
+	if err != nil {
+		t.Fatalf("DecodeQueue: unexpected error: %v", err)
+	} else if diff := gocmp.Diff(gotQueue.Slice(), q.Slice()); diff != "" {
+		t.Errorf("DecodeQueue (-got, +want):\n%s", diff)
+	}
+	if empty, err := mcodec.DecodeQueue(d, mcodec.String); err != nil {
+		t.Fatalf("DecodeQueue: unexpected error: %v", err)
+	} else if !empty.IsEmpty() {
+		t.Errorf("DecodeQueue: got %v, want empty", empty.Slice())
+	}
+
+	// The stream is exhausted.
+	if _, err := d.ReadRecord(); err != io.EOF {
+		t.Errorf("ReadRecord: got %v, want %v", err, io.EOF)
+	}
+
+	t.Run("Streaming", func(t *testing.T) {
+		d := mcodec.NewDecoder(bytes.NewReader(data))
+		for range mcodec.ReadSeq(d, mcodec.Int64) {
+			break // stop early, the rest should be skipped
+		}
+		var keys []string
+		for k, v := range mcodec.ReadSeq2(d, mcodec.String, mcodec.Int64) {
+			keys = append(keys, k)
+			if v < 0 {
+				break
+			}
+		}
+		if diff := gocmp.Diff(keys, []string{"apple", "pear"}); diff != "" {
+			t.Errorf("ReadSeq2 keys (-got, +want):\n%s", diff)
+		}
+		got := slices.Collect(mcodec.ReadSeq(d, mcodec.String))
+		if diff := gocmp.Diff(got, []string{"x", "", "z"}); diff != "" {
+			t.Errorf("ReadSeq (-got, +want):\n%s", diff)
+		}
+		if err := d.Err(); err != nil {
+			t.Errorf("Err: unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		for n := 1; n < len(data); n++ {
+			d := mcodec.NewDecoder(bytes.NewReader(data[:n]))
+			if _, err := mcodec.DecodeSet(d, mcodec.Int64); err == nil {
+				continue // the set is complete, the next one is short
+			}
+			if err := d.Err(); !errors.Is(err, io.ErrUnexpectedEOF) {
+				t.Errorf("Prefix %d: got error %v, want %v", n, err, io.ErrUnexpectedEOF)
+			}
+		}
+	})
+
+	t.Run("LongPrefix", func(t *testing.T) {
+		// A record whose length prefix is much larger than the input should
+		// fail without allocating space for the claimed length.
+		input := binary.AppendUvarint([]byte{1}, mcodec.MaxRecordLen)
+		input = append(input, "abc"...)
+
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		d := mcodec.NewDecoder(bytes.NewReader(input))
+		if _, err := mcodec.DecodeQueue(d, mcodec.String); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("DecodeQueue: got %v, want %v", err, io.ErrUnexpectedEOF)
+		}
+		runtime.ReadMemStats(&after)
+		if n := after.TotalAlloc - before.TotalAlloc; n > 1<<20 {
+			t.Errorf("DecodeQueue allocated %d bytes, want ≤ %d", n, 1<<20)
+		}
+	})
+
+	t.Run("MapUnchanged", func(t *testing.T) {
+		// A map is not modified if decoding fails partway through.
+		var buf bytes.Buffer
+		if err := mcodec.EncodeMap(mcodec.NewEncoder(&buf), m, mcodec.String, mcodec.Int64); err != nil {
+			t.Fatalf("EncodeMap: unexpected error: %v", err)
+		}
+		d := mcodec.NewDecoder(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+		got := mapOf("cherry", 5)
+		if err := mcodec.DecodeMap(d, got, mcodec.String, mcodec.Int64); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("DecodeMap: got %v, want %v", err, io.ErrUnexpectedEOF)
+		}
+		if got, want := got.String(), mapOf("cherry", 5).String(); got != want {
+			t.Errorf("DecodeMap: got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		d := mcodec.NewDecoder(strings.NewReader(""))
+		if _, err := mcodec.DecodeQueue(d, mcodec.String); err != io.EOF {
+			t.Errorf("DecodeQueue: got %v, want %v", err, io.EOF)
+		}
+	})
+
+	t.Run("BadValue", func(t *testing.T) {
+		var buf bytes.Buffer
+		e := mcodec.NewEncoder(&buf)
+		if err := mcodec.EncodeQueue(e, queueOf("ok", "bad"), mcodec.String); err != nil {
+			t.Fatalf("EncodeQueue: unexpected error: %v", err)
+		}
+		d := mcodec.NewDecoder(&buf)
+		if q, err := mcodec.DecodeQueue(d, mcodec.Int64); err == nil {
+			t.Errorf("DecodeQueue: got %v, want error", q.Slice())
+		}
+	})
+}
+
+func TestCodecs(t *testing.T) {
+	for _, v := range []int64{0, 1, -1, 1 << 40, -1 << 62} {
+		got, err := mcodec.Int64.Parse(mcodec.Int64.Append(nil, v))
+		if err != nil || got != v {
+			t.Errorf("Int64 %d: got %d, %v", v, got, err)
+		}
+	}
+	for _, v := range []uint64{0, 1, 127, 128, 1<<64 - 1} {
+		got, err := mcodec.Uint64.Parse(mcodec.Uint64.Append(nil, v))
+		if err != nil || got != v {
+			t.Errorf("Uint64 %d: got %d, %v", v, got, err)
+		}
+	}
+	if v, err := mcodec.Int64.Parse([]byte{0x80}); err == nil {
+		t.Errorf("Int64 invalid: got %d, want error", v)
+	}
+	if v, err := mcodec.Uint64.Parse([]byte{1, 2}); err == nil {
+		t.Errorf("Uint64 trailing data: got %d, want error", v)
+	}
+
+	in := []byte("hello")
+	out, err := mcodec.Bytes.Parse(in)
+	if err != nil || string(out) != "hello" {
+		t.Errorf("Bytes: got %q, %v", out, err)
+	}
+	in[0] = 'j'
+	if string(out) != "hello" {
+		t.Errorf("Bytes: result aliases its input: %q", out)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	set := mapset.New("a", "b", "c")
+	m := mapOf("apple", 1, "pear", -2, "plum", 300)
+	q := queueOf("x", "", "z")
+
+	var buf bytes.Buffer
+	if err := mcodec.EncodeSetJSON(&buf, set); err != nil {
+		t.Fatalf("EncodeSetJSON: unexpected error: %v", err)
+	}
+	if err := mcodec.EncodeMapJSON(&buf, m); err != nil {
+		t.Fatalf("EncodeMapJSON: unexpected error: %v", err)
+	}
+	if err := mcodec.EncodeQueueJSON(&buf, q); err != nil {
+		t.Fatalf("EncodeQueueJSON: unexpected error: %v", err)
+	}
+	if err := mcodec.EncodeQueueJSON(&buf, queue.New[string]()); err != nil {
+		t.Fatalf("EncodeQueueJSON: unexpected error: %v", err)
+	}
+	text := buf.String()
+	t.Logf("Encoded:\n%s", text)
+
+	// The ordered containers have a stable encoding.
+	lines := strings.Split(text, "\n")
+	if diff := gocmp.Diff(lines[1:], []string{
+		`[["apple",1],["pear",-2],["plum",300]]`,
+		`["x","","z"]`,
+		`[]`,
+		``,
+	}); diff != "" {
+		t.Errorf("Encoded JSON (-got, +want):\n%s", diff)
+	}
+
+	d := mcodec.NewJSONDecoder(strings.NewReader(text))
+	gotSet, err := mcodec.DecodeSetJSON[string](d)
+	if err != nil {
+		t.Fatalf("DecodeSetJSON: unexpected error: %v", err)
+	} else if !gotSet.Equals(set) {
+		t.Errorf("DecodeSetJSON: got %v, want %v", gotSet, set)
+	}
+
+	gotMap := omap.New[string, int64]()
+	if err := mcodec.DecodeMapJSON(d, gotMap); err != nil {
+		t.Fatalf("DecodeMapJSON: unexpected error: %v", err)
+	} else if got, want := gotMap.String(), m.String(); got != want {
+		t.Errorf("DecodeMapJSON: got %v, want %v", got, want)
+	}
+
+	// The encoding of a map matches its own MarshalJSON method.
+	if mdata, err := json.Marshal(m); err != nil {
+		t.Fatalf("Marshal map: unexpected error: %v", err)
+	} else if got, want := string(mdata), lines[1]; got != want {
+		t.Errorf("Marshal map: got %#q, want %#q", got, want)
+	}
+
+	gotQueue, err := mcodec.DecodeQueueJSON[string](d)
+	if err != nil {
+		t.Fatalf("DecodeQueueJSON: unexpected error: %v", err)
+	} else if diff := gocmp.Diff(gotQueue.Slice(), q.Slice()); diff != "" {
+		t.Errorf("DecodeQueueJSON (-got, +want):\n%s", diff)
+	}
+	if empty, err := mcodec.DecodeQueueJSON[string](d); err != nil {
+		t.Fatalf("DecodeQueueJSON: unexpected error: %v", err)
+	} else if !empty.IsEmpty() {
+		t.Errorf("DecodeQueueJSON: got %v, want empty", empty.Slice())
+	}
+	if _, err := mcodec.DecodeQueueJSON[string](d); err != io.EOF {
+		t.Errorf("DecodeQueueJSON at end: got %v, want %v", err, io.EOF)
+	}
+
+	t.Run("Streaming", func(t *testing.T) {
+		d := mcodec.NewJSONDecoder(strings.NewReader(`[1, 2, 3] null [["a", [1]], ["b", [2, 3]]] [4]`))
+		for range mcodec.ReadJSONSeq[int](d) {
+			break // stop early, the rest should be skipped
+		}
+		if got := slices.Collect(mcodec.ReadJSONSeq[int](d)); got != nil {
+			t.Errorf("ReadJSONSeq(null): got %v, want empty", got)
+		}
+		for k, v := range mcodec.ReadJSONSeq2[string, []int](d) {
+			if k != "a" || !slices.Equal(v, []int{1}) {
+				t.Errorf("ReadJSONSeq2: got %q, %v; want a, [1]", k, v)
+			}
+			break
+		}
+		if got := slices.Collect(mcodec.ReadJSONSeq[int](d)); !slices.Equal(got, []int{4}) {
+			t.Errorf("ReadJSONSeq: got %v, want [4]", got)
+		}
+		if err := d.Err(); err != nil {
+			t.Errorf("Err: unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		decodeQueue := func(d *mcodec.JSONDecoder) error {
+			_, err := mcodec.DecodeQueueJSON[int](d)
+			return err
+		}
+		decodeMap := func(d *mcodec.JSONDecoder) error {
+			m := omap.New[string, int]()
+			err := mcodec.DecodeMapJSON(d, m)
+			if err != nil && m.Len() != 0 {
+				t.Errorf("DecodeMapJSON: got %v after error, want empty", m)
+			}
+			return err
+		}
+		tests := []struct {
+			input  string
+			decode func(*mcodec.JSONDecoder) error
+		}{
+			{`{"a": 1}`, decodeQueue},               // not an array
+			{`[1, 2`, decodeQueue},                  // truncated
+			{`["a", "b"]`, decodeQueue},             // wrong element type
+			{`[1, 2] [3, "four"]`, decodeQueue},     // error in a later array
+			{`[["a", 1, 2]]`, decodeMap},            // pair too long
+			{`[["a"]]`, decodeMap},                  // pair too short
+			{`[{"a": 1}]`, decodeMap},               // pair is not an array
+			{`[["a", 1], ["b", "two"]]`, decodeMap}, // error after a valid pair
+		}
+		for _, tc := range tests {
+			d := mcodec.NewJSONDecoder(strings.NewReader(tc.input))
+			var err error
+			for err == nil {
+				err = tc.decode(d)
+			}
+			if err == io.EOF {
+				t.Errorf("Input %#q: got EOF, want a decoding error", tc.input)
+			} else {
+				t.Logf("Input %#q: got expected error: %v", tc.input, err)
+			}
+		}
+	})
+}
@@ -21,10 +21,22 @@
 // This modifies the diff in-place to merge adjacent and overlapping chunks, so
 // that their contexts are not repeated.
 //
+// To disregard changes that affect only uninteresting lines, such as
+// timestamps or build IDs, call IgnoreMatching before adding context:
+//
+//	diff.IgnoreMatching(regexp.MustCompile(`^Built at `))
+//
 // These operations can be chained to produce a (unified) diff with context:
 //
 //	diff := mdiff.New(lhs, rhs).AddContext(3).Unify()
 //
+// These operations modify the diff. To render one diff with several amounts
+// of context, use [Diff.FormatWith], which leaves the diff unchanged.
+//
+// To measure how much two inputs have in common, use [Similarity]. The
+// [FindRenames] function uses this measure to pair up deleted and added files
+// that are likely to be renames of each other.
+//
 // # Output
 //
 // To write a diff in textual format, use the [Diff.Format] or [Patch.Format]
@@ -68,6 +80,7 @@ package mdiff
 
 import (
 	"io"
+	"regexp"
 	"slices"
 
 	"github.com/creachadair/mds/slice"
@@ -171,6 +184,40 @@ func (d *Diff) AddContext(n int) *Diff {
 	return d
 }
 
+// IgnoreMatching updates d in-place to discard any chunk all of whose deleted
+// and inserted lines match at least one of the given regular expressions, so
+// that such lines are treated as equal. This is similar to the -I flag of GNU
+// diff. The text of the remaining chunks is not affected. IgnoreMatching
+// returns d.
+//
+// IgnoreMatching should be called before AddContext, since the lines of an
+// ignored chunk are not equal and will not be used as context for other
+// chunks. It does not modify the original edit sequence in d.Edits.
+func (d *Diff) IgnoreMatching(res ...*regexp.Regexp) *Diff {
+	if len(res) == 0 || len(d.Chunks) == 0 {
+		return d
+	}
+	ignore := func(lines []string) bool {
+		for _, line := range lines {
+			if !slices.ContainsFunc(res, func(re *regexp.Regexp) bool {
+				return re.MatchString(line)
+			}) {
+				return false
+			}
+		}
+		return true
+	}
+	d.Chunks = slices.DeleteFunc(d.Chunks, func(c *Chunk) bool {
+		for _, e := range c.Edits {
+			if e.Op != slice.OpEmit && !(ignore(e.X) && ignore(e.Y)) {
+				return false
+			}
+		}
+		return true
+	})
+	return d
+}
+
 // Unify updates d in-place to merge chunks that adjoin or overlap.  For a Diff
 // returned by New, this is a no-op; however AddContext may cause chunks to
 // abut or to overlap. Unify returns d.
@@ -183,6 +230,78 @@ func (d *Diff) Unify() *Diff { d.Chunks = UnifyChunks(d.Chunks); return d }
 // If fi == nil, no file header is generated.
 func (d *Diff) Format(w io.Writer, f FormatFunc, fi *FileInfo) error { return f(w, d.Chunks, fi) }
 
+// FormatOptions are settings for [Diff.FormatWith].
+// A zero value adds no context and merges only adjoining chunks.
+type FormatOptions struct {
+	// Context is the number of lines of context to add before and after each
+	// chunk, as for AddContext.
+	Context int
+
+	// MergeGap is the largest number of unchanged lines that may separate two
+	// chunks, after context has been added, for them to be merged into a
+	// single chunk including those lines. Chunks that adjoin or overlap are
+	// always merged, as for Unify.
+	MergeGap int
+}
+
+// FormatWith renders a diff in textual format using the specified format
+// function, as for Format, after adding context and merging chunks according
+// to opts. Unlike AddContext and Unify, FormatWith does not modify d, so the
+// same diff can be rendered with different options.
+func (d *Diff) FormatWith(w io.Writer, f FormatFunc, fi *FileInfo, opts FormatOptions) error {
+	// Copy the chunks so that adding context does not affect d.
+	tmp := &Diff{Left: d.Left, Right: d.Right, Chunks: make([]*Chunk, len(d.Chunks))}
+	for i, c := range d.Chunks {
+		cp := *c
+		cp.Edits = slices.Clone(c.Edits)
+		for j := range cp.Edits {
+			// Merging chunks may append to context edits, which must not
+			// clobber the storage of the original.
+			cp.Edits[j].X = slices.Clip(cp.Edits[j].X)
+		}
+		tmp.Chunks[i] = &cp
+	}
+	tmp.AddContext(opts.Context).Unify()
+	if opts.MergeGap > 0 {
+		tmp.mergeGaps(opts.MergeGap)
+	}
+	return f(w, tmp.Chunks, fi)
+}
+
+// mergeGaps updates d in-place to merge consecutive chunks that are separated
+// by at most n unchanged lines. The chunks must not overlap.
+func (d *Diff) mergeGaps(n int) {
+	if len(d.Chunks) == 0 {
+		return
+	}
+	merged := d.Chunks[:1]
+	for _, c := range d.Chunks[1:] {
+		last := slice.At(merged, -1)
+		gap := c.LStart - last.LEnd
+		if gap > n {
+			merged = append(merged, c)
+			continue
+		}
+
+		// The lines between the chunks are equal on both sides.
+		ctx := slices.Clip(d.Left[last.LEnd-1 : c.LStart-1])
+		if end := slice.PtrAt(last.Edits, -1); end.Op == slice.OpEmit {
+			end.X = append(end.X, ctx...)
+		} else {
+			last.Edits = append(last.Edits, Edit{Op: slice.OpEmit, X: ctx})
+		}
+		edits := c.Edits
+		if start := edits[0]; start.Op == slice.OpEmit {
+			end := slice.PtrAt(last.Edits, -1)
+			end.X = append(end.X, start.X...)
+			edits = edits[1:]
+		}
+		last.Edits = append(last.Edits, edits...)
+		last.LEnd, last.REnd = c.LEnd, c.REnd
+	}
+	d.Chunks = merged
+}
+
 // findContext returns slices of up to n strings before and after the specified
 // chunk that are equal on the left and right sides of the diff.  Either or
 // both slices may be empty if there are no such lines.
@@ -3,6 +3,7 @@ package mdiff_test
 import (
 	"bytes"
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -114,6 +115,124 @@ func TestNoAlias(t *testing.T) {
 	}
 }
 
+func TestIgnoreMatching(t *testing.T) {
+	lhs := lines("start", "built at 10:15", "a", "b", "c", "id 1234", "d", "end")
+	rhs := lines("start", "built at 11:30", "a", "B", "c", "id 5678", "extra", "d", "end")
+	stamp := regexp.MustCompile(`^built at `)
+	ident := regexp.MustCompile(`^id \d+$`)
+
+	chunkLines := func(cs []*mdiff.Chunk) [][]int {
+		var out [][]int
+		for _, c := range cs {
+			out = append(out, []int{c.LStart, c.LEnd, c.RStart, c.REnd})
+		}
+		return out
+	}
+	tests := []struct {
+		name string
+		res  []*regexp.Regexp
+		want [][]int
+	}{
+		{"None", nil, [][]int{{2, 3, 2, 3}, {4, 5, 4, 5}, {6, 7, 6, 8}}},
+		{"Stamp", []*regexp.Regexp{stamp}, [][]int{{4, 5, 4, 5}, {6, 7, 6, 8}}},
+
+		// The chunk with the ID also has a line that does not match.
+		{"Both", []*regexp.Regexp{stamp, ident}, [][]int{{4, 5, 4, 5}, {6, 7, 6, 8}}},
+		{"All", []*regexp.Regexp{stamp, ident, regexp.MustCompile(`^(?i:b|extra)$`)}, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d := mdiff.New(lhs, rhs)
+			nedit := len(d.Edits)
+			d.IgnoreMatching(tc.res...)
+			logChunks(t, d.Chunks)
+			if diff := gocmp.Diff(chunkLines(d.Chunks), tc.want); diff != "" {
+				t.Errorf("Chunks (-got, +want):\n%s", diff)
+			}
+			if len(d.Edits) != nedit {
+				t.Errorf("Edits changed: got %d, want %d", len(d.Edits), nedit)
+			}
+		})
+	}
+
+	t.Run("Format", func(t *testing.T) {
+		var buf bytes.Buffer
+		mdiff.New(lhs, rhs).IgnoreMatching(stamp).AddContext(1).Unify().Format(&buf, mdiff.Unified, nil)
+		const want = `@@ -3,5 +3,6 @@
+ a
+-b
++B
+ c
+-id 1234
++id 5678
++extra
+ d
+`
+		if got := buf.String(); got != want {
+			t.Errorf("Format: got:\n%s\nwant:\n%s", got, want)
+		}
+	})
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		lhs, rhs []string
+		want     float64
+	}{
+		{nil, nil, 1},
+		{lines("a"), nil, 0},
+		{nil, lines("a"), 0},
+		{lines("a", "b"), lines("a", "b"), 1},
+		{lines("a", "b"), lines("c", "d"), 0},
+		{lines("a", "b", "c"), lines("a", "c"), 0.8},
+		{lines("a", "b", "c", "d"), lines("x", "b", "y", "d"), 0.5},
+	}
+	for _, tc := range tests {
+		if got := mdiff.Similarity(tc.lhs, tc.rhs); got != tc.want {
+			t.Errorf("Similarity(%q, %q): got %v, want %v", tc.lhs, tc.rhs, got, tc.want)
+		}
+	}
+}
+
+func TestFindRenames(t *testing.T) {
+	deleted := [][]string{
+		lines("package a", "func A() {}", "func B() {}", "func C() {}"), // 0
+		lines("all", "new", "stuff"),                                    // 1
+		lines("x", "y", "z", "w"),                                       // 2
+	}
+	added := [][]string{
+		lines("x", "y", "z", "q"), // 0
+		lines("package b", "func A() {}", "func B() {}", "func C() {}"), // 1
+		lines("x", "y", "z", "w"), // 2
+		lines("unrelated"),        // 3
+	}
+	type rename = mdiff.Rename
+	tests := []struct {
+		threshold float64
+		want      []rename
+	}{
+		{0.5, []rename{{0, 1, 0.75}, {2, 2, 1}}},
+		{0.8, []rename{{2, 2, 1}}},
+		{1.1, nil},
+	}
+	for _, tc := range tests {
+		got := mdiff.FindRenames(deleted, added, tc.threshold)
+		if diff := gocmp.Diff(got, tc.want); diff != "" {
+			t.Errorf("FindRenames %v (-got, +want):\n%s", tc.threshold, diff)
+		}
+	}
+
+	// Once its best match is claimed, a file pairs with its next best.
+	got := mdiff.FindRenames(deleted[2:], added[:3], 0.5)
+	if diff := gocmp.Diff(got, []rename{{0, 2, 1}}); diff != "" {
+		t.Errorf("FindRenames (-got, +want):\n%s", diff)
+	}
+	got = mdiff.FindRenames([][]string{deleted[2], deleted[2]}, added[:3], 0.5)
+	if diff := gocmp.Diff(got, []rename{{0, 2, 1}, {1, 0, 0.75}}); diff != "" {
+		t.Errorf("FindRenames (-got, +want):\n%s", diff)
+	}
+}
+
 func TestFormat(t *testing.T) {
 	t.Run("Normal", func(t *testing.T) {
 		d := mdiff.New(lhsLines, rhsLines)
@@ -209,6 +328,56 @@ func TestFormat(t *testing.T) {
 	})
 }
 
+func TestFormatWith(t *testing.T) {
+	format := func(d *mdiff.Diff) string {
+		var buf bytes.Buffer
+		d.Format(&buf, mdiff.Unified, nil)
+		return buf.String()
+	}
+	formatWith := func(d *mdiff.Diff, opts mdiff.FormatOptions) string {
+		var buf bytes.Buffer
+		d.FormatWith(&buf, mdiff.Unified, nil, opts)
+		return buf.String()
+	}
+
+	t.Run("Context", func(t *testing.T) {
+		d := mdiff.New(lhsLines, rhsLines)
+		orig := format(d)
+
+		// Rendering with different amounts of context gives the same result as
+		// modifying a fresh diff, and does not affect d.
+		for _, n := range []int{0, 1, 3, 5, 3} {
+			want := format(mdiff.New(lhsLines, rhsLines).AddContext(n).Unify())
+			if got := formatWith(d, mdiff.FormatOptions{Context: n}); got != want {
+				t.Errorf("FormatWith context %d: got:\n%s\nwant:\n%s", n, got, want)
+			}
+		}
+		if got := format(d); got != orig {
+			t.Errorf("FormatWith modified the diff: got:\n%s\nwant:\n%s", got, orig)
+		}
+	})
+
+	t.Run("MergeGap", func(t *testing.T) {
+		lhs := strings.Fields("a b c d e f g h i j")
+		rhs := strings.Fields("a B c d e f g H i j")
+		d := mdiff.New(lhs, rhs)
+
+		tests := []struct {
+			gap  int
+			want string
+		}{
+			{2, "@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n@@ -7,3 +7,3 @@\n g\n-h\n+H\n i\n"},
+			{3, "@@ -1,9 +1,9 @@\n a\n-b\n+B\n c\n d\n e\n f\n g\n-h\n+H\n i\n"},
+		}
+		for _, tc := range tests {
+			got := formatWith(d, mdiff.FormatOptions{Context: 1, MergeGap: tc.gap})
+			if diff := gocmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("FormatWith gap %d (-got, +want):\n%s", tc.gap, diff)
+			}
+		}
+	})
+}
+
 func TestRead(t *testing.T) {
 	t.Run("Normal", func(t *testing.T) {
 		p, err := mdiff.Read(strings.NewReader(odiff))
@@ -294,6 +463,87 @@ func TestRead(t *testing.T) {
 	})
 }
 
+func TestEqualPatch(t *testing.T) {
+	t.Run("Producers", func(t *testing.T) {
+		// A normal diff read from text should match the same diff constructed
+		// directly from the inputs, which has no context.
+		p, err := mdiff.Read(strings.NewReader(odiff))
+		if err != nil {
+			t.Fatalf("Read: unexpected error: %v", err)
+		}
+		d := mdiff.New(lhsLines, rhsLines)
+		if !mdiff.EqualPatch(p, &mdiff.Patch{Chunks: d.Chunks}) {
+			t.Error("EqualPatch(Read, New): got false, want true")
+		}
+
+		// A unified diff with context should match the same with context added.
+		u, err := mdiff.ReadUnified(strings.NewReader(udiff))
+		if err != nil {
+			t.Fatalf("ReadUnified: unexpected error: %v", err)
+		}
+		c := mdiff.New(lhsLines, rhsLines).AddContext(3).Unify()
+		if !mdiff.EqualPatch(u, &mdiff.Patch{Chunks: c.Chunks}) {
+			t.Error("EqualPatch(ReadUnified, AddContext): got false, want true")
+		}
+		if mdiff.EqualPatch(p, u) {
+			t.Error("EqualPatch(Read, ReadUnified): got true, want false")
+		}
+	})
+
+	t.Run("Normalize", func(t *testing.T) {
+		e := func(op slice.EditOp, x, y string) mdiff.Edit {
+			return mdiff.Edit{Op: op, X: strings.Fields(x), Y: strings.Fields(y)}
+		}
+		a := &mdiff.Patch{Chunks: []*mdiff.Chunk{{
+			Edits:  []mdiff.Edit{e(slice.OpReplace, "k", "z")},
+			LStart: 10, LEnd: 11, RStart: 12, REnd: 13,
+		}, {
+			Edits: []mdiff.Edit{
+				e(slice.OpEmit, "a", ""),
+				e(slice.OpEmit, "b", ""),
+				e(slice.OpCopy, "", "x"),
+				e(slice.OpReplace, "c", "y"),
+				e(slice.OpDrop, "d", ""),
+				e(slice.OpEmit, "", ""),
+				e(slice.OpEmit, "e", ""),
+			},
+			LStart: 1, LEnd: 6, RStart: 1, REnd: 6,
+		}, {
+			LStart: 8, LEnd: 8, RStart: 9, REnd: 9, // no edits
+		}}}
+		b := &mdiff.Patch{Chunks: []*mdiff.Chunk{{
+			Edits: []mdiff.Edit{
+				e(slice.OpEmit, "a b", ""),
+				e(slice.OpDrop, "c d", ""),
+				e(slice.OpCopy, "", "x y"),
+				e(slice.OpEmit, "e", ""),
+			},
+			LStart: 1, LEnd: 6, RStart: 1, REnd: 6,
+		}, {
+			Edits: []mdiff.Edit{
+				e(slice.OpDrop, "k", ""),
+				e(slice.OpCopy, "", "z"),
+			},
+			LStart: 10, LEnd: 11, RStart: 12, REnd: 13,
+		}}}
+		if !mdiff.EqualPatch(a, b) {
+			t.Error("EqualPatch: got false, want true")
+		}
+		if len(a.Chunks) != 3 || a.Chunks[0].LStart != 10 {
+			t.Errorf("EqualPatch modified its input: %+v", a.Chunks)
+		}
+
+		if diff := gocmp.Diff(a.Normalize(), b, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("Normalize (-got, +want):\n%s", diff)
+		}
+
+		b.Chunks[1].REnd++
+		if mdiff.EqualPatch(a, b) {
+			t.Error("EqualPatch: got true, want false")
+		}
+	})
+}
+
 func logDiff(t *testing.T, d *mdiff.Diff) {
 	t.Helper()
 	t.Logf("Input left: %d lines, right: %d lines; diff has %d edits",
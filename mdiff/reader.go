@@ -2,9 +2,11 @@ package mdiff
 
 import (
 	"bufio"
+	"cmp"
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -21,6 +23,93 @@ type Patch struct {
 // Format renders a patch in textual format using the specified format function.
 func (p *Patch) Format(w io.Writer, f FormatFunc) error { return f(w, p.Chunks, p.FileInfo) }
 
+// Normalize rewrites the chunks of p into a canonical form, and returns p.
+// The chunks are sorted by starting line, and within each chunk each run of
+// consecutive changes is replaced by a single deletion followed by a single
+// insertion, and consecutive context lines are merged. Empty edits and chunks
+// with no edits are removed.
+//
+// Two patches that describe the same changes with the same context have equal
+// normalized chunks, even if they were read from different formats.
+func (p *Patch) Normalize() *Patch { p.Chunks = normalizeChunks(p.Chunks); return p }
+
+// EqualPatch reports whether a and b describe the same changes, that is,
+// whether their chunks are equal after normalization. The file info of the
+// patches is not compared. EqualPatch does not modify a or b.
+func EqualPatch(a, b *Patch) bool {
+	ac, bc := normalizeChunks(a.Chunks), normalizeChunks(b.Chunks)
+	return slices.EqualFunc(ac, bc, func(x, y *Chunk) bool {
+		return x.LStart == y.LStart && x.LEnd == y.LEnd &&
+			x.RStart == y.RStart && x.REnd == y.REnd &&
+			slices.EqualFunc(x.Edits, y.Edits, func(e, f Edit) bool {
+				return e.Op == f.Op && slices.Equal(e.X, f.X) && slices.Equal(e.Y, f.Y)
+			})
+	})
+}
+
+// normalizeChunks returns a normalized copy of cs, as described by
+// [Patch.Normalize]. The input chunks and their edits are not modified.
+func normalizeChunks(cs []*Chunk) []*Chunk {
+	var out []*Chunk
+	for _, c := range cs {
+		es := normalizeEdits(c.Edits)
+		if len(es) == 0 {
+			continue
+		}
+		out = append(out, &Chunk{
+			Edits:  es,
+			LStart: c.LStart, LEnd: c.LEnd,
+			RStart: c.RStart, REnd: c.REnd,
+		})
+	}
+	slices.SortStableFunc(out, func(a, b *Chunk) int {
+		if v := cmp.Compare(a.LStart, b.LStart); v != 0 {
+			return v
+		}
+		return cmp.Compare(a.RStart, b.RStart)
+	})
+	return out
+}
+
+// normalizeEdits returns a normalized copy of es, as described by
+// [Patch.Normalize]. The input edits are not modified.
+func normalizeEdits(es []Edit) []Edit {
+	var out []Edit
+	var del, ins []string // pending changes
+	flush := func() {
+		if len(del) != 0 {
+			out = append(out, Edit{Op: slice.OpDrop, X: del})
+		}
+		if len(ins) != 0 {
+			out = append(out, Edit{Op: slice.OpCopy, Y: ins})
+		}
+		del, ins = nil, nil
+	}
+	for _, e := range es {
+		switch e.Op {
+		case slice.OpDrop:
+			del = append(del, e.X...)
+		case slice.OpCopy:
+			ins = append(ins, e.Y...)
+		case slice.OpReplace:
+			del = append(del, e.X...)
+			ins = append(ins, e.Y...)
+		case slice.OpEmit:
+			if len(e.X) == 0 {
+				continue
+			}
+			flush()
+			if last := slice.PtrAt(out, -1); last != nil && last.Op == slice.OpEmit {
+				last.X = append(last.X, e.X...)
+			} else {
+				out = append(out, Edit{Op: slice.OpEmit, X: slices.Clone(e.X)})
+			}
+		}
+	}
+	flush()
+	return out
+}
+
 // ReadGitPatch reads a sequence of unified diff [patches] in the format
 // produced by "git diff -p" with default settings. The commit metadata and
 // header lines are ignored.
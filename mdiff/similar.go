@@ -0,0 +1,63 @@
+package mdiff
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/creachadair/mds/slice"
+)
+
+// Similarity reports how similar lhs and rhs are, as a score between 0 and 1.
+// The score is the fraction of all the lines of both inputs that belong to a
+// longest common subsequence, so that identical inputs score 1 and inputs with
+// no lines in common score 0. Two empty inputs are considered identical.
+func Similarity(lhs, rhs []string) float64 {
+	if len(lhs)+len(rhs) == 0 {
+		return 1
+	}
+	common := len(slice.LCS(lhs, rhs))
+	return float64(2*common) / float64(len(lhs)+len(rhs))
+}
+
+// A Rename records a likely pairing of a deleted file with an added file.
+type Rename struct {
+	Deleted int     // offset of the deleted file
+	Added   int     // offset of the added file
+	Score   float64 // similarity of the contents, as reported by Similarity
+}
+
+// FindRenames pairs the contents of deleted files with the contents of added
+// files that are likely to be renames of them, such as the files removed and
+// created by a multi-file patch. Each file is paired at most once, and only if
+// its similarity score is at least threshold.
+//
+// Pairs are chosen greedily in decreasing order of similarity, so that each
+// file is paired with its most similar counterpart not already claimed. Ties
+// are broken in favor of earlier files. The results are ordered by the offset
+// of the deleted file.
+func FindRenames(deleted, added [][]string, threshold float64) []Rename {
+	var cands []Rename
+	for i, d := range deleted {
+		for j, a := range added {
+			if s := Similarity(d, a); s >= threshold {
+				cands = append(cands, Rename{Deleted: i, Added: j, Score: s})
+			}
+		}
+	}
+	slices.SortStableFunc(cands, func(a, b Rename) int {
+		return cmp.Compare(b.Score, a.Score) // decreasing similarity
+	})
+
+	var out []Rename
+	usedD := make([]bool, len(deleted))
+	usedA := make([]bool, len(added))
+	for _, c := range cands {
+		if usedD[c.Deleted] || usedA[c.Added] {
+			continue
+		}
+		usedD[c.Deleted], usedA[c.Added] = true, true
+		out = append(out, c)
+	}
+	slices.SortFunc(out, func(a, b Rename) int { return cmp.Compare(a.Deleted, b.Deleted) })
+	return out
+}
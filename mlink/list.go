@@ -1,5 +1,7 @@
 package mlink
 
+import "iter"
+
 // A List is a singly-linked ordered list. A zero value is ready for use.
 //
 // The methods of a List value do not allow direct modification of the list.
@@ -99,6 +101,38 @@ func (lst *List[T]) Find(f func(T) bool) *Cursor[T] {
 	return &cur
 }
 
+// InsertSorted inserts v into lst before the first element greater than v, as
+// determined by cmp, and returns a cursor to the newly-added element. If lst
+// is sorted by cmp, it remains sorted after insertion, and v is placed after
+// any existing elements equal to it.
+//
+// This method takes time proportional to the position of v in the list.
+func (lst *List[T]) InsertSorted(v T, cmp func(a, b T) int) *Cursor[T] {
+	cur := lst.cfirst()
+	for !cur.AtEnd() && cmp(cur.Get(), v) <= 0 {
+		cur.Next()
+	}
+	cur.Push(v)
+	return &cur
+}
+
+// MergeSortedInto merges the values of src into dst in a single pass, as if
+// by calling InsertSorted for each. Both dst and src must be sorted by cmp,
+// and dst remains sorted after the merge. Values from src are placed after
+// any elements of dst equal to them.
+//
+// This function takes time proportional to the combined length of dst and
+// src.
+func MergeSortedInto[T any](dst *List[T], src iter.Seq[T], cmp func(a, b T) int) {
+	cur := dst.cfirst()
+	for v := range src {
+		for !cur.AtEnd() && cmp(cur.Get(), v) <= 0 {
+			cur.Next()
+		}
+		cur.Add(v)
+	}
+}
+
 func (lst *List[T]) cfirst() Cursor[T] { return Cursor[T]{pred: &lst.first} }
 
 // A Cursor represents a location in a list.  A nil *Cursor is not valid, and
@@ -1,6 +1,8 @@
 package mlink_test
 
 import (
+	"cmp"
+	"slices"
 	"testing"
 
 	"github.com/creachadair/mds/internal/mdtest"
@@ -172,6 +174,43 @@ func TestList(t *testing.T) {
 	checkList()
 }
 
+func TestInsertSorted(t *testing.T) {
+	var lst mlink.List[int]
+	checkList := func(want ...int) { t.Helper(); mdtest.CheckContents(t, &lst, want) }
+
+	for _, v := range []int{5, 3, 8, 1, 5, 9, 0} {
+		c := lst.InsertSorted(v, cmp.Compare)
+		if got := c.Get(); got != v {
+			t.Errorf("InsertSorted(%d): cursor at %d", v, got)
+		}
+	}
+	checkList(0, 1, 3, 5, 5, 8, 9)
+
+	mlink.MergeSortedInto(&lst, slices.Values([]int{-1, 2, 5, 6, 10, 11}), cmp.Compare)
+	checkList(-1, 0, 1, 2, 3, 5, 5, 5, 6, 8, 9, 10, 11)
+
+	lst.Clear()
+	mlink.MergeSortedInto(&lst, slices.Values([]int{1, 2, 3}), cmp.Compare)
+	checkList(1, 2, 3)
+
+	t.Run("Stable", func(t *testing.T) {
+		type pair struct {
+			Key string
+			Tag int
+		}
+		byKey := func(a, b pair) int { return cmp.Compare(a.Key, b.Key) }
+
+		var lst mlink.List[pair]
+		lst.InsertSorted(pair{"b", 1}, byKey)
+		lst.InsertSorted(pair{"a", 1}, byKey)
+		lst.InsertSorted(pair{"b", 2}, byKey)
+		mlink.MergeSortedInto(&lst, slices.Values([]pair{{"a", 2}, {"b", 3}, {"c", 1}}), byKey)
+		mdtest.CheckContents(t, &lst, []pair{
+			{"a", 1}, {"a", 2}, {"b", 1}, {"b", 2}, {"b", 3}, {"c", 1},
+		})
+	})
+}
+
 func mustPanic(f func()) func(*testing.T) {
 	return func(t *testing.T) {
 		t.Helper()
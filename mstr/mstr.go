@@ -2,7 +2,10 @@
 package mstr
 
 import (
+	"bufio"
 	"cmp"
+	"io"
+	"iter"
 	"strings"
 )
 
@@ -41,6 +44,45 @@ func Lines(s string) []string {
 	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
 }
 
+// LinesSeq returns an iterator over the lines of s, as defined by [Lines].
+// Unlike Lines, the lines are found as needed rather than all at once.
+func LinesSeq(s string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for s != "" {
+			line, rest, ok := strings.Cut(s, "\n")
+			if !yield(line) || !ok {
+				return
+			}
+			s = rest
+		}
+	}
+}
+
+// ScanLinesSeq returns an iterator over the lines read from r, as defined by
+// [Lines].  Lines are read from r as needed, and there is no limit on the
+// length of a line.  If reading r fails, the iterator yields an empty line
+// with the error, and stops.
+func ScanLinesSeq(r io.Reader) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		br := bufio.NewReader(r)
+		for {
+			line, err := br.ReadString('\n')
+			if err == io.EOF {
+				if line != "" {
+					yield(line, nil)
+				}
+				return
+			} else if err != nil {
+				yield("", err)
+				return
+			}
+			if !yield(line[:len(line)-1], nil) {
+				return
+			}
+		}
+	}
+}
+
 // Split splits its argument on sep. It is a convenience function for
 // [strings.Split], except that it returns empty if s == "".
 func Split(s, sep string) []string {
@@ -89,6 +131,71 @@ func CompareNatural(a, b string) int {
 	return cmp.Compare(a, b)
 }
 
+// CompareVersion compares its arguments as version strings, consisting of
+// a core version of dot-separated fields, optionally followed by a hyphen and
+// a pre-release tag of dot-separated fields, for example "1.10.2-rc.1".
+//
+// The fields of each version are compared in order using [CompareNatural], so
+// that "1.10" is after "1.9". If one core version has fewer fields than the
+// other, the missing fields are treated as "0", so that "1.2" and "1.2.0" are
+// equal. A version with a pre-release tag precedes the same version without,
+// and otherwise the tags are compared field by field, with a prefix preceding
+// a longer tag. An optional leading "v" or "V" and any build metadata following
+// a "+" are ignored.
+//
+// CompareVersion does not check that its arguments are well-formed, and
+// handles non-numeric fields (for example "1.2a") without error. It returns -1
+// if a < b, 0 if a == b, and +1 if a > b.
+func CompareVersion(a, b string) int {
+	ca, pa := splitVersion(a)
+	cb, pb := splitVersion(b)
+	if c := compareFields(ca, cb, "0"); c != 0 {
+		return c
+	} else if pa == "" || pb == "" {
+		// A version without a pre-release tag follows one with.
+		return cmp.Compare(len(pb), len(pa))
+	}
+	return compareFields(pa, pb, "")
+}
+
+// splitVersion splits a version string into its core version and pre-release
+// tag, discarding a leading "v" and any build metadata.
+func splitVersion(s string) (core, pre string) {
+	if s != "" && (s[0] == 'v' || s[0] == 'V') {
+		s = s[1:]
+	}
+	s, _, _ = strings.Cut(s, "+")
+	core, pre, _ = strings.Cut(s, "-")
+	return core, pre
+}
+
+// compareFields compares the dot-separated fields of a and b in order using
+// CompareNatural. If pad != "", a missing field is treated as pad; otherwise
+// the string with fewer fields precedes the other if all its fields are equal.
+func compareFields(a, b, pad string) int {
+	for a != "" || b != "" {
+		fa, ra, aok := strings.Cut(a, ".")
+		fb, rb, bok := strings.Cut(b, ".")
+		if a == "" && !aok {
+			if pad == "" {
+				return -1
+			}
+			fa = pad
+		}
+		if b == "" && !bok {
+			if pad == "" {
+				return 1
+			}
+			fb = pad
+		}
+		if c := CompareNatural(fa, fb); c != 0 {
+			return c
+		}
+		a, b = ra, rb
+	}
+	return 0
+}
+
 // parseInt reports whether s begins with a run of one or more decimal digits,
 // and if so returns the value of that run, along with the unconsumed tail of
 // the string.
@@ -1,7 +1,12 @@
 package mstr_test
 
 import (
+	"errors"
+	"io"
+	"slices"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/creachadair/mds/mstr"
 	gocmp "github.com/google/go-cmp/cmp"
@@ -62,7 +67,41 @@ func TestLines(t *testing.T) {
 		if diff := gocmp.Diff(mstr.Lines(tc.input), tc.want); diff != "" {
 			t.Errorf("Lines %q (-got, +want):\n%s", tc.input, diff)
 		}
+		if diff := gocmp.Diff(slices.Collect(mstr.LinesSeq(tc.input)), tc.want); diff != "" {
+			t.Errorf("LinesSeq %q (-got, +want):\n%s", tc.input, diff)
+		}
+
+		var got []string
+		for line, err := range mstr.ScanLinesSeq(strings.NewReader(tc.input)) {
+			if err != nil {
+				t.Fatalf("ScanLinesSeq %q: unexpected error: %v", tc.input, err)
+			}
+			got = append(got, line)
+		}
+		if diff := gocmp.Diff(got, tc.want); diff != "" {
+			t.Errorf("ScanLinesSeq %q (-got, +want):\n%s", tc.input, diff)
+		}
 	}
+
+	t.Run("ScanError", func(t *testing.T) {
+		bad := errors.New("bad")
+		r := io.MultiReader(strings.NewReader("a\nb"), iotest.ErrReader(bad))
+		var got []string
+		var gotErr error
+		for line, err := range mstr.ScanLinesSeq(r) {
+			if err != nil {
+				gotErr = err
+				break
+			}
+			got = append(got, line)
+		}
+		if !errors.Is(gotErr, bad) {
+			t.Errorf("ScanLinesSeq: got error %v, want %v", gotErr, bad)
+		}
+		if diff := gocmp.Diff(got, []string{"a"}); diff != "" {
+			t.Errorf("ScanLinesSeq (-got, +want):\n%s", diff)
+		}
+	})
 }
 
 func TestSplit(t *testing.T) {
@@ -146,3 +185,61 @@ func TestCompareNatural(t *testing.T) {
 		}
 	}
 }
+
+func TestCompareVersion(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"1", "1", 0},
+		{"1.2.3", "1.2.3", 0},
+
+		// Numeric fields compare by value, not lexicographically.
+		{"1.9", "1.10", -1},
+		{"1.10.0", "1.9.9", 1},
+		{"2.0", "10.0", -1},
+		{"1.01", "1.1", 0},
+
+		// Missing fields are zero.
+		{"1.2", "1.2.0", 0},
+		{"1.2.0.0", "1.2", 0},
+		{"1.2", "1.2.1", -1},
+		{"1", "0.9", 1},
+
+		// A leading "v" and build metadata are ignored.
+		{"v1.2.3", "1.2.3", 0},
+		{"V1.2.3", "v1.2.3", 0},
+		{"1.2.3+build.5", "1.2.3+build.7", 0},
+		{"v1.2.3-rc.1+x", "1.2.3-rc.1", 0},
+
+		// A pre-release precedes the release.
+		{"1.0.0-alpha", "1.0.0", -1},
+		{"1.0.0", "1.0.0-rc.1", 1},
+		{"1.0-rc.1", "1.0.0", -1},
+		{"1.0.0-rc.1", "0.9.9", 1},
+
+		// Pre-release tags compare field by field (cf. the semver spec).
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-alpha.beta", "1.0.0-beta", -1},
+		{"1.0.0-beta", "1.0.0-beta.2", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"1.0.0-beta.11", "1.0.0-rc.1", -1},
+		{"1.0.0-rc9", "1.0.0-rc10", -1},
+		{"1.0.0-rc.1", "1.0.0-rc.1", 0},
+
+		// Non-numeric fields are tolerated.
+		{"1.2a", "1.2b", -1},
+		{"1.2a", "1.10", -1},
+		{"1.x", "1.0", 1},
+	}
+	for _, tc := range tests {
+		if got := mstr.CompareVersion(tc.a, tc.b); got != tc.want {
+			t.Errorf("CompareVersion(%q, %q): got %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+		if got := mstr.CompareVersion(tc.b, tc.a); got != -tc.want {
+			t.Errorf("CompareVersion(%q, %q): got %v, want %v", tc.b, tc.a, got, -tc.want)
+		}
+	}
+}
@@ -1,6 +1,14 @@
 // Package mtest is a support library for writing tests.
 package mtest
 
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
 // TB is the subset of the testing.TB interface used by this package.
 type TB interface {
 	Cleanup(func())
@@ -27,6 +35,70 @@ func MustPanicf(t TB, f func(), msg string, args ...any) (val any) {
 	return
 }
 
+// NoPanic executes a function f that is expected not to panic. If f panics,
+// NoPanic logs a fatal error in t reporting the value recovered from the
+// panic. This is useful in fuzz targets, to report a panic as an ordinary
+// test failure rather than a crash.
+func NoPanic(t TB, f func()) {
+	t.Helper()
+	defer func() {
+		if v := recover(); v != nil {
+			t.Fatalf("unexpected panic: %v", v)
+		}
+	}()
+	f()
+}
+
+// MustErr logs a fatal error in t if err == nil. If target != nil, it also
+// logs a fatal error if err does not match target, as reported by [errors.Is].
+// It returns err.
+func MustErr(t TB, err, target error) error {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("got nil error, want error")
+	} else if target != nil && !errors.Is(err, target) {
+		t.Fatalf("got error %v, want %v", err, target)
+	}
+	return err
+}
+
+// MustNoErr logs a fatal error in t if err != nil.
+func MustNoErr(t TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Diff returns a human-readable report of the differences between got and
+// want, or "" if they are equal. It uses [cmp.Diff] with the given options,
+// and treats empty and nil slices and maps as equal. In the report, lines
+// beginning with "-" are from got, and lines beginning with "+" are from want.
+func Diff(got, want any, opts ...cmp.Option) string {
+	return cmp.Diff(got, want, append(opts, cmpopts.EquateEmpty())...)
+}
+
+// A Case is a case of a table-driven test for [RunCases].
+type Case[In, Out any] struct {
+	Name  string // the name of the subtest for this case
+	Input In     // the input to the function under test
+	Want  Out    // the expected output
+}
+
+// RunCases runs a subtest of t for each of the given cases, which calls f with
+// the input of the case, and reports an error if the result differs from the
+// expected output of the case. Results are compared by [Diff] with opts.
+func RunCases[In, Out any](t *testing.T, cases []Case[In, Out], f func(In) Out, opts ...cmp.Option) {
+	t.Helper()
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			if diff := Diff(f(tc.Input), tc.Want, opts...); diff != "" {
+				t.Errorf("Input %v: result (-got, +want):\n%s", tc.Input, diff)
+			}
+		})
+	}
+}
+
 // Swap replaces the target of p with v, and restores the original value when
 // the governing test exits. It returns the original value.
 func Swap[T any](t TB, p *T, v T) T {
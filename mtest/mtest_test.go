@@ -1,7 +1,10 @@
 package mtest_test
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/creachadair/mds/mtest"
@@ -93,3 +96,77 @@ func TestSwap(t *testing.T) {
 		t.Errorf("Test value after is %q, want original", testValue)
 	}
 }
+
+func TestNoPanic(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		mtest.NoPanic(t, func() {})
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		var s testStub
+		mtest.NoPanic(&s, func() { panic("bad") })
+		if !s.failed {
+			t.Error("Test did not fail as expected")
+		}
+		if !strings.Contains(s.text, "bad") {
+			t.Errorf("Wrong message: got %q, want it to mention the panic", s.text)
+		}
+	})
+}
+
+func TestMustErr(t *testing.T) {
+	wrapped := fmt.Errorf("wrapped: %w", io.EOF)
+	tests := []struct {
+		err, target error
+		fail        bool
+	}{
+		{nil, nil, true},
+		{nil, io.EOF, true},
+		{io.EOF, nil, false},
+		{io.EOF, io.EOF, false},
+		{wrapped, io.EOF, false},
+		{io.EOF, io.ErrUnexpectedEOF, true},
+	}
+	for _, tc := range tests {
+		var s testStub
+		if got := mtest.MustErr(&s, tc.err, tc.target); got != tc.err {
+			t.Errorf("MustErr(%v, %v): got %v, want %v", tc.err, tc.target, got, tc.err)
+		}
+		if s.failed != tc.fail {
+			t.Errorf("MustErr(%v, %v): failed=%v, want %v", tc.err, tc.target, s.failed, tc.fail)
+		}
+	}
+}
+
+func TestMustNoErr(t *testing.T) {
+	mtest.MustNoErr(t, nil)
+
+	var s testStub
+	mtest.MustNoErr(&s, errors.New("bad"))
+	if !s.failed {
+		t.Error("Test did not fail as expected")
+	}
+	if !strings.Contains(s.text, "bad") {
+		t.Errorf("Wrong message: got %q, want it to mention the error", s.text)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	if diff := mtest.Diff([]int(nil), []int{}); diff != "" {
+		t.Errorf("Diff(nil, empty): got %q, want empty", diff)
+	}
+	if diff := mtest.Diff(map[string]int{}, map[string]int(nil)); diff != "" {
+		t.Errorf("Diff(empty, nil): got %q, want empty", diff)
+	}
+	if diff := mtest.Diff([]int{1, 2}, []int{1, 3}); diff == "" {
+		t.Error("Diff([1 2], [1 3]): got empty, want a difference")
+	}
+}
+
+func TestRunCases(t *testing.T) {
+	mtest.RunCases(t, []mtest.Case[string, []string]{
+		{Name: "Empty", Input: "", Want: nil},
+		{Name: "One", Input: "a", Want: []string{"a"}},
+		{Name: "Several", Input: "a b  c", Want: []string{"a", "b", "c"}},
+	}, strings.Fields)
+}
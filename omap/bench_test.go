@@ -0,0 +1,250 @@
+package omap_test
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/creachadair/mds/omap"
+)
+
+// These benchmarks compare a Map with two common alternatives for keeping
+// key-value pairs in order: a built-in map whose keys are sorted on demand,
+// and a slice of pairs kept sorted by key.
+//
+// Except where noted, each benchmark operation processes all n keys, so the
+// cost per key is the reported time divided by n.
+
+var benchSizes = []int{100, 1_000, 10_000}
+
+// A pair is a key-value pair stored in a sorted slice.
+type pair struct {
+	key, value int
+}
+
+func comparePair(a, b pair) int { return cmp.Compare(a.key, b.key) }
+
+// benchKeys returns n distinct keys in random order.
+func benchKeys(n int) []int {
+	rng := rand.New(rand.NewPCG(1, 2))
+	keys := make([]int, n)
+	for i, v := range rng.Perm(n) {
+		keys[i] = 2 * v
+	}
+	return keys
+}
+
+func buildMap(keys []int) omap.Map[int, int] {
+	m := omap.New[int, int]()
+	for _, k := range keys {
+		m.Set(k, k)
+	}
+	return m
+}
+
+func buildBuiltin(keys []int) map[int]int {
+	m := make(map[int]int)
+	for _, k := range keys {
+		m[k] = k
+	}
+	return m
+}
+
+func buildSlice(keys []int) []pair {
+	var s []pair
+	for _, k := range keys {
+		i, ok := slices.BinarySearchFunc(s, pair{key: k}, comparePair)
+		if ok {
+			s[i].value = k
+		} else {
+			s = slices.Insert(s, i, pair{k, k})
+		}
+	}
+	return s
+}
+
+// BenchmarkInsert measures adding n keys one at a time to an empty container.
+func BenchmarkInsert(b *testing.B) {
+	for _, n := range benchSizes {
+		keys := benchKeys(n)
+		b.Run(fmt.Sprintf("n=%d/omap", n), func(b *testing.B) {
+			for range b.N {
+				buildMap(keys)
+			}
+		})
+		b.Run(fmt.Sprintf("n=%d/map", n), func(b *testing.B) {
+			for range b.N {
+				buildBuiltin(keys)
+			}
+		})
+		b.Run(fmt.Sprintf("n=%d/slice", n), func(b *testing.B) {
+			for range b.N {
+				buildSlice(keys)
+			}
+		})
+	}
+}
+
+// BenchmarkUpdate measures replacing the values of n existing keys.
+func BenchmarkUpdate(b *testing.B) {
+	for _, n := range benchSizes {
+		keys := benchKeys(n)
+		b.Run(fmt.Sprintf("n=%d/omap", n), func(b *testing.B) {
+			m := buildMap(keys)
+			b.ResetTimer()
+			for i := range b.N {
+				for _, k := range keys {
+					m.Set(k, i)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("n=%d/map", n), func(b *testing.B) {
+			m := buildBuiltin(keys)
+			b.ResetTimer()
+			for i := range b.N {
+				for _, k := range keys {
+					m[k] = i
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("n=%d/slice", n), func(b *testing.B) {
+			s := buildSlice(keys)
+			b.ResetTimer()
+			for i := range b.N {
+				for _, k := range keys {
+					j, _ := slices.BinarySearchFunc(s, pair{key: k}, comparePair)
+					s[j].value = i
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkLookup measures looking up n keys, half of which are present.
+func BenchmarkLookup(b *testing.B) {
+	for _, n := range benchSizes {
+		keys := benchKeys(n)
+		probe := slices.Clone(keys)
+		for i := range probe {
+			probe[i] += i % 2 // odd keys are not present
+		}
+		b.Run(fmt.Sprintf("n=%d/omap", n), func(b *testing.B) {
+			m := buildMap(keys)
+			b.ResetTimer()
+			for range b.N {
+				for _, k := range probe {
+					m.GetOK(k)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("n=%d/map", n), func(b *testing.B) {
+			m := buildBuiltin(keys)
+			b.ResetTimer()
+			for range b.N {
+				for _, k := range probe {
+					_, _ = m[k]
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("n=%d/slice", n), func(b *testing.B) {
+			s := buildSlice(keys)
+			b.ResetTimer()
+			for range b.N {
+				for _, k := range probe {
+					_, _ = slices.BinarySearchFunc(s, pair{key: k}, comparePair)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkScan measures visiting all n keys in order. For the built-in map,
+// this includes sorting the keys.
+func BenchmarkScan(b *testing.B) {
+	for _, n := range benchSizes {
+		keys := benchKeys(n)
+		b.Run(fmt.Sprintf("n=%d/omap", n), func(b *testing.B) {
+			m := buildMap(keys)
+			b.ResetTimer()
+			for range b.N {
+				var sum int
+				for it := m.First(); it.IsValid(); it.Next() {
+					sum += it.Value()
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("n=%d/map", n), func(b *testing.B) {
+			m := buildBuiltin(keys)
+			b.ResetTimer()
+			for range b.N {
+				var sum int
+				for _, k := range slices.Sorted(maps.Keys(m)) {
+					sum += m[k]
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("n=%d/slice", n), func(b *testing.B) {
+			s := buildSlice(keys)
+			b.ResetTimer()
+			for range b.N {
+				var sum int
+				for _, p := range s {
+					sum += p.value
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkChurn measures 1000 rounds of deleting the least key and inserting
+// a new greatest key, keeping the size of the container fixed.
+func BenchmarkChurn(b *testing.B) {
+	const rounds = 1000
+	for _, n := range benchSizes {
+		keys := benchKeys(n)
+		b.Run(fmt.Sprintf("n=%d/omap", n), func(b *testing.B) {
+			m := buildMap(keys)
+			next := 2 * n
+			b.ResetTimer()
+			for range b.N {
+				for range rounds {
+					m.PopFirst()
+					m.Set(next, next)
+					next++
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("n=%d/map", n), func(b *testing.B) {
+			m := buildBuiltin(keys)
+			next := 2 * n
+			b.ResetTimer()
+			for range b.N {
+				for range rounds {
+					// Finding the least key requires a scan.
+					least := next
+					for k := range m {
+						least = min(least, k)
+					}
+					delete(m, least)
+					m[next] = next
+					next++
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("n=%d/slice", n), func(b *testing.B) {
+			s := buildSlice(keys)
+			next := 2 * n
+			b.ResetTimer()
+			for range b.N {
+				for range rounds {
+					s = append(s[:0], s[1:]...)
+					s = append(s, pair{next, next})
+					next++
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package omap_test
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"maps"
 	"testing"
 
 	"github.com/creachadair/mds/mtest"
@@ -99,10 +104,275 @@ func TestZero(t *testing.T) {
 	if it := zero.First().Seek("whatever"); it.IsValid() {
 		t.Errorf("Seek(whatever): unexected key %q=%q", it.Key(), it.Value())
 	}
+	if it := zero.DescendSeek("whatever"); it.IsValid() {
+		t.Errorf("DescendSeek(whatever): unexected key %q=%q", it.Key(), it.Value())
+	}
+	for key, value := range zero.Range("a", "z", 0) {
+		t.Errorf("Range: unexpected key %q=%q", key, value)
+	}
+	if k, v, ok := zero.PopFirst(); ok {
+		t.Errorf("PopFirst: unexpected key %q=%q", k, v)
+	}
+	if k, v, ok := zero.PopLast(); ok {
+		t.Errorf("PopLast: unexpected key %q=%q", k, v)
+	}
 	zero.Clear() // don't panic
 
 	mtest.MustPanicf(t, func() { zero.Set("bad", "mojo") },
 		"Set on a zero map should panic")
+	mtest.MustPanicf(t, func() { zero.GetOrSet("bad", func() string { return "mojo" }) },
+		"GetOrSet on a zero map should panic")
+}
+
+func TestSetAll(t *testing.T) {
+	m := omap.Collect(maps.All(map[string]int{"apple": 1, "pear": 2, "plum": 3}))
+	if got, want := m.String(), `omap[apple:1 pear:2 plum:3]`; got != want {
+		t.Errorf("Collect: got %q, want %q", got, want)
+	}
+
+	pairs := func(yield func(string, int) bool) {
+		for _, key := range []string{"cherry", "pear", "kiwi", "cherry", "apple"} {
+			if !yield(key, len(key)) {
+				return
+			}
+		}
+	}
+	if n := m.SetAll(pairs); n != 2 {
+		t.Errorf("SetAll: got %d new keys, want 2", n)
+	}
+	if got, want := m.String(), `omap[apple:5 cherry:6 kiwi:4 pear:4 plum:3]`; got != want {
+		t.Errorf("SetAll: got %q, want %q", got, want)
+	}
+	if m.Len() != 5 {
+		t.Errorf("Len: got %d, want 5", m.Len())
+	}
+}
+
+func TestGetOrSet(t *testing.T) {
+	m := omap.New[string, []int]()
+	calls := 0
+	newValue := func() []int { calls++; return []int{calls} }
+
+	for _, tc := range []struct {
+		key   string
+		want  []int
+		added bool
+	}{
+		{"a", []int{1}, true},
+		{"b", []int{2}, true},
+		{"a", []int{1}, false},
+		{"c", []int{3}, true},
+		{"b", []int{2}, false},
+	} {
+		got, added := m.GetOrSet(tc.key, newValue)
+		if diff := gocmp.Diff(got, tc.want); diff != "" || added != tc.added {
+			t.Errorf("GetOrSet(%q): got %v, %v; want %v, %v", tc.key, got, added, tc.want, tc.added)
+		}
+	}
+	if calls != 3 {
+		t.Errorf("newValue was called %d times, want 3", calls)
+	}
+	if got, want := m.String(), `omap[a:[1] b:[2] c:[3]]`; got != want {
+		t.Errorf("GetOrSet: got %q, want %q", got, want)
+	}
+
+	// The result reports whether the key was added, not whether it was present.
+	if _, added := m.GetOrSet("a", func() []int { panic("unexpected call") }); added {
+		t.Error(`GetOrSet("a") on a hit: got added=true, want false`)
+	}
+	if _, added := m.GetOrSet("z", newValue); !added {
+		t.Error(`GetOrSet("z") on a miss: got added=false, want true`)
+	}
+}
+
+func TestPopFirstLast(t *testing.T) {
+	m := omap.New[int, string]()
+	for i, v := range []string{"zero", "one", "two", "three"} {
+		m.Set(i, v)
+	}
+	for _, tc := range []struct {
+		first bool
+		key   int
+		value string
+	}{
+		{true, 0, "zero"},
+		{false, 3, "three"},
+		{true, 1, "one"},
+		{true, 2, "two"},
+	} {
+		pop, name := m.PopLast, "PopLast"
+		if tc.first {
+			pop, name = m.PopFirst, "PopFirst"
+		}
+		if k, v, ok := pop(); !ok || k != tc.key || v != tc.value {
+			t.Errorf("%s: got (%d, %q, %v), want (%d, %q, true)", name, k, v, ok, tc.key, tc.value)
+		}
+	}
+	if k, v, ok := m.PopFirst(); ok {
+		t.Errorf("PopFirst on empty map: got (%d, %q, true), want false", k, v)
+	}
+}
+
+func TestEqualFunc(t *testing.T) {
+	eq := func(a, b int) bool { return a == b }
+	mk := func(kvs ...any) omap.Map[string, int] {
+		m := omap.New[string, int]()
+		for i := 0; i+1 < len(kvs); i += 2 {
+			m.Set(kvs[i].(string), kvs[i+1].(int))
+		}
+		return m
+	}
+	var zero omap.Map[string, int]
+
+	for _, tc := range []struct {
+		a, b omap.Map[string, int]
+		want bool
+	}{
+		{zero, zero, true},
+		{zero, mk(), true},
+		{mk(), zero, true},
+		{mk("a", 1), zero, false},
+		{mk("a", 1, "b", 2), mk("b", 2, "a", 1), true},
+		{mk("a", 1, "b", 2), mk("a", 1, "b", 3), false},
+		{mk("a", 1, "b", 2), mk("a", 1, "c", 2), false},
+		{mk("a", 1, "b", 2), mk("a", 1), false},
+	} {
+		if got := tc.a.EqualFunc(tc.b, eq); got != tc.want {
+			t.Errorf("EqualFunc(%v, %v): got %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	m := omap.Collect(maps.All(map[string]int{"apple": 1, "pear": 2, "plum": 3}))
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal: unexpected error: %v", err)
+	}
+	const want = `[["apple",1],["pear",2],["plum",3]]`
+	if got := string(data); got != want {
+		t.Errorf("Marshal: got %#q, want %#q", got, want)
+	}
+	dst := omap.New[string, int]()
+	if err := json.Unmarshal(data, &dst); err != nil {
+		t.Fatalf("Unmarshal: unexpected error: %v", err)
+	}
+	if got, want := dst.String(), m.String(); got != want {
+		t.Errorf("Unmarshal: got %q, want %q", got, want)
+	}
+
+	// Keys out of order are sorted, and the first of any duplicates is kept.
+	if err := json.Unmarshal([]byte(`[["plum",3],["apple",1],["pear",2],["apple",5]]`), &dst); err != nil {
+		t.Fatalf("Unmarshal: unexpected error: %v", err)
+	}
+	if got, want := dst.String(), m.String(); got != want {
+		t.Errorf("Unmarshal unsorted: got %q, want %q", got, want)
+	}
+
+	// Malformed input reports an error and leaves the map unchanged.
+	for _, bad := range []string{`{}`, `[{"Key":"a","Value":1}]`, `[["a"]]`, `[["a",1,2]]`, `[["a","b"]]`} {
+		if err := json.Unmarshal([]byte(bad), &dst); err == nil {
+			t.Errorf("Unmarshal %#q: got nil, want error", bad)
+		}
+	}
+	if got, want := dst.String(), m.String(); got != want {
+		t.Errorf("After errors: got %q, want %q", got, want)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(m); err != nil {
+		t.Fatalf("Gob encode: unexpected error: %v", err)
+	}
+	gdst := omap.New[string, int]()
+	if err := gob.NewDecoder(&buf).Decode(&gdst); err != nil {
+		t.Fatalf("Gob decode: unexpected error: %v", err)
+	}
+	if got, want := gdst.String(), m.String(); got != want {
+		t.Errorf("Gob decode: got %q, want %q", got, want)
+	}
+
+	// A zero map encodes as empty, but cannot be decoded into.
+	var zero omap.Map[string, int]
+	if data, err := json.Marshal(zero); err != nil || string(data) != "[]" {
+		t.Errorf("Marshal zero: got %#q, %v; want [], nil", data, err)
+	}
+	if err := json.Unmarshal(data, &zero); err == nil {
+		t.Error("Unmarshal into zero map: got nil, want error")
+	}
+}
+
+func TestRange(t *testing.T) {
+	m := omap.New[int, string]()
+	for i := 10; i <= 50; i += 10 {
+		m.Set(i, fmt.Sprint(i))
+	}
+
+	t.Run("DescendSeek", func(t *testing.T) {
+		tests := []struct {
+			key  int
+			want []int
+		}{
+			{5, nil},
+			{10, []int{10}},
+			{25, []int{20, 10}},
+			{30, []int{30, 20, 10}},
+			{99, []int{50, 40, 30, 20, 10}},
+		}
+		for _, tc := range tests {
+			var got []int
+			for it := m.DescendSeek(tc.key); it.IsValid(); it.Prev() {
+				got = append(got, it.Key())
+			}
+			if diff := gocmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("DescendSeek %d (-got, +want):\n%s", tc.key, diff)
+			}
+		}
+	})
+
+	tests := []struct {
+		lo, hi int
+		flags  omap.RangeFlag
+		want   []int
+	}{
+		{10, 50, 0, []int{10, 20, 30, 40, 50}},
+		{0, 100, 0, []int{10, 20, 30, 40, 50}},
+		{20, 40, 0, []int{20, 30, 40}},
+		{20, 40, omap.ExcludeLo, []int{30, 40}},
+		{20, 40, omap.ExcludeHi, []int{20, 30}},
+		{20, 40, omap.ExcludeLo | omap.ExcludeHi, []int{30}},
+		{15, 45, omap.ExcludeLo | omap.ExcludeHi, []int{20, 30, 40}},
+		{30, 30, 0, []int{30}},
+		{30, 30, omap.ExcludeLo, nil},
+		{30, 30, omap.ExcludeHi, nil},
+		{31, 39, 0, nil},
+		{40, 20, 0, nil},
+		{60, 100, 0, nil},
+		{0, 5, 0, nil},
+
+		{10, 50, omap.Descending, []int{50, 40, 30, 20, 10}},
+		{0, 100, omap.Descending, []int{50, 40, 30, 20, 10}},
+		{20, 40, omap.Descending, []int{40, 30, 20}},
+		{20, 40, omap.ExcludeLo | omap.Descending, []int{40, 30}},
+		{20, 40, omap.ExcludeHi | omap.Descending, []int{30, 20}},
+		{15, 45, omap.Descending, []int{40, 30, 20}},
+		{30, 30, omap.Descending, []int{30}},
+		{30, 30, omap.ExcludeHi | omap.Descending, nil},
+		{40, 20, omap.Descending, nil},
+		{0, 5, omap.Descending, nil},
+	}
+	for _, tc := range tests {
+		var got []int
+		for key, value := range m.Range(tc.lo, tc.hi, tc.flags) {
+			if want := fmt.Sprint(key); value != want {
+				t.Errorf("Range: key %d has value %q, want %q", key, value, want)
+			}
+			got = append(got, key)
+		}
+		if diff := gocmp.Diff(got, tc.want); diff != "" {
+			t.Errorf("Range(%d, %d, %v) (-got, +want):\n%s", tc.lo, tc.hi, tc.flags, diff)
+		}
+	}
 }
 
 func TestIterEdit(t *testing.T) {
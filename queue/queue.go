@@ -2,6 +2,8 @@
 package queue
 
 import (
+	"iter"
+
 	"github.com/creachadair/mds/slice"
 )
 
@@ -14,6 +16,15 @@ type Queue[T any] struct {
 	vs   []T
 	head int
 	n    int
+
+	stats Stats
+}
+
+// Stats records usage statistics for a Queue.
+type Stats struct {
+	MaxLen  int // the largest number of values held at once (high-water mark)
+	Added   int // the total number of values added by Add or Push
+	Removed int // the total number of values removed by Pop, PopLast, or PopInto
 }
 
 // New constructs a new empty queue.
@@ -32,7 +43,7 @@ func (q *Queue[T]) Add(v T) {
 			pos -= len(q.vs)
 		}
 		q.vs[pos] = v
-		q.n++
+		q.added()
 		return
 	}
 
@@ -47,7 +58,7 @@ func (q *Queue[T]) Add(v T) {
 	// The buffer is in the initial regime, head == 0.
 	w := append(q.vs, v)
 	q.vs = w[:cap(w)]
-	q.n++
+	q.added()
 }
 
 // Push adds v to the front of q.
@@ -60,7 +71,7 @@ func (q *Queue[T]) Push(v T) {
 		}
 		q.vs[pos] = v
 		q.head = pos
-		q.n++
+		q.added()
 		return
 	}
 
@@ -75,7 +86,14 @@ func (q *Queue[T]) Push(v T) {
 	q.vs = w[:cap(w)]
 	q.head = len(q.vs) - 1
 	q.vs[q.head] = v
+	q.added()
+}
+
+// added records the addition of a value to q.
+func (q *Queue[T]) added() {
 	q.n++
+	q.stats.Added++
+	q.stats.MaxLen = max(q.stats.MaxLen, q.n)
 }
 
 // IsEmpty reports whether q is empty.
@@ -84,6 +102,18 @@ func (q *Queue[T]) IsEmpty() bool { return q.n == 0 }
 // Len reports the number of entries in q.
 func (q *Queue[T]) Len() int { return q.n }
 
+// Cap reports the number of values q can hold before it must grow its buffer.
+func (q *Queue[T]) Cap() int { return len(q.vs) }
+
+// Stats returns usage statistics for q. The statistics are cumulative over
+// the lifetime of q, and are not affected by Clear. Use ResetStats to reset
+// them.
+func (q *Queue[T]) Stats() Stats { return q.stats }
+
+// ResetStats resets the usage statistics for q, as if q were newly created
+// with its current contents.
+func (q *Queue[T]) ResetStats() { q.stats = Stats{MaxLen: q.n} }
+
 // Clear discards all the values in q, leaving it empty.
 func (q *Queue[T]) Clear() { q.vs, q.head, q.n = nil, 0, 0 }
 
@@ -121,6 +151,7 @@ func (q *Queue[T]) Pop() (T, bool) {
 	}
 	out := q.vs[q.head]
 	q.n--
+	q.stats.Removed++
 	if q.n == 0 {
 		q.head = 0 // reset to initial conditions
 	} else {
@@ -142,12 +173,35 @@ func (q *Queue[T]) PopLast() (T, bool) {
 	}
 	out := q.vs[pos]
 	q.n--
+	q.stats.Removed++
 	if q.n == 0 {
 		q.head = 0 // reset to initial conditions
 	}
 	return out, true
 }
 
+// PopInto removes up to len(dst) of the frontmost (oldest) values from q and
+// copies them into dst in order from oldest to newest. It returns the number
+// of values removed, which is less than len(dst) only if q had fewer values.
+func (q *Queue[T]) PopInto(dst []T) int {
+	n := min(len(dst), q.n)
+	if n == 0 {
+		return 0
+	}
+
+	// The values occupy at most two contiguous spans of the buffer, as in From.
+	nc := copy(dst[:n], q.vs[q.head:])
+	copy(dst[nc:n], q.vs)
+	q.n -= n
+	q.stats.Removed += n
+	if q.n == 0 {
+		q.head = 0 // reset to initial conditions
+	} else {
+		q.head = (q.head + n) % len(q.vs)
+	}
+	return n
+}
+
 // Each is a range function that calls f with each value, in q, in order from
 // oldest to newest.  If f returns false, Each returns immediately.
 func (q *Queue[T]) Each(f func(T) bool) {
@@ -160,6 +214,40 @@ func (q *Queue[T]) Each(f func(T) bool) {
 	}
 }
 
+// From returns an iterator over the values of q in order from offset n to the
+// newest, without copying. As with Peek, negative offsets count forward from
+// the end of the queue. If n is out of range, From yields nothing. The queue
+// must not be modified while the iterator is running.
+func (q *Queue[T]) From(n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if n < 0 {
+			n += q.n
+		}
+		if n < 0 || n >= q.n {
+			return
+		}
+
+		// The values occupy at most two contiguous spans of the buffer, the
+		// second of which (if any) begins at offset 0.
+		start := q.head + n
+		if start >= len(q.vs) {
+			start -= len(q.vs)
+		}
+		rest := q.n - n
+		for _, v := range q.vs[start:min(start+rest, len(q.vs))] {
+			if !yield(v) {
+				return
+			}
+			rest--
+		}
+		for _, v := range q.vs[:rest] {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
 // Slice returns a slice of the values of q in order from oldest to newest.
 // If q is empty, Slice returns nil.
 func (q *Queue[T]) Slice() []T {
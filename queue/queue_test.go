@@ -3,6 +3,7 @@ package queue_test
 import (
 	"flag"
 	"math/rand/v2"
+	"slices"
 	"testing"
 
 	"github.com/creachadair/mds/internal/mdtest"
@@ -124,6 +125,7 @@ func TestQueueRandom(t *testing.T) {
 		NumAdd   int
 		NumPop   int
 		NumClear int
+		NumTaken int // successful pops
 	}
 	get := func(z int) int {
 		if z < 0 || z >= len(has) {
@@ -167,6 +169,7 @@ func TestQueueRandom(t *testing.T) {
 				}
 				continue
 			}
+			stats.NumTaken++
 			want := has[0]
 			has = has[1:]
 			if !ok || got != want {
@@ -189,6 +192,9 @@ func TestQueueRandom(t *testing.T) {
 				if got, ok := q.Peek(r); !ok || got != has[r] {
 					t.Errorf("Peek(%d): got (%d, %v), want (%d, true)", r, got, ok, has[r])
 				}
+				if got := slices.Collect(q.From(r)); !slices.Equal(got, has[r:]) {
+					t.Errorf("From(%d): got %v, want %v", r, got, has[r:])
+				}
 			}
 		case op < doClear:
 			stats.NumClear++
@@ -201,4 +207,133 @@ func TestQueueRandom(t *testing.T) {
 	}
 	t.Logf("Queue at exit (n=%d): %v", q.Len(), q.Slice())
 	t.Logf("Stats: %+v", stats)
+
+	stats.MaxLen = max(stats.MaxLen, len(has))
+	want := queue.Stats{MaxLen: stats.MaxLen, Added: stats.NumAdd, Removed: stats.NumTaken}
+	if got := q.Stats(); got != want {
+		t.Errorf("Stats: got %+v, want %+v", got, want)
+	}
+}
+
+func TestFrom(t *testing.T) {
+	q := queue.NewSize[int](5)
+	for i := range 5 {
+		q.Add(i + 1)
+	}
+	q.Pop()
+	q.Pop()
+	q.Add(6)
+	q.Add(7) // the queue is now [3 4 5 6 7], wrapped around its buffer
+
+	tests := []struct {
+		n    int
+		want []int
+	}{
+		{0, []int{3, 4, 5, 6, 7}},
+		{2, []int{5, 6, 7}},
+		{3, []int{6, 7}},
+		{4, []int{7}},
+		{5, nil},
+		{-1, []int{7}},
+		{-3, []int{5, 6, 7}},
+		{-5, []int{3, 4, 5, 6, 7}},
+		{-6, nil},
+	}
+	for _, tc := range tests {
+		if got := slices.Collect(q.From(tc.n)); !slices.Equal(got, tc.want) {
+			t.Errorf("From(%d): got %v, want %v", tc.n, got, tc.want)
+		}
+	}
+
+	var got []int
+	for v := range q.From(1) {
+		got = append(got, v)
+		if v == 5 {
+			break
+		}
+	}
+	if want := []int{4, 5}; !slices.Equal(got, want) {
+		t.Errorf("From(1) with break: got %v, want %v", got, want)
+	}
+}
+
+func TestPopInto(t *testing.T) {
+	q := queue.NewSize[int](5)
+	for i := range 5 {
+		q.Add(i + 1)
+	}
+	q.Pop()
+	q.Pop()
+	q.Add(6)
+	q.Add(7) // the queue is now [3 4 5 6 7], wrapped around its buffer
+
+	buf := make([]int, 2)
+	if n := q.PopInto(buf); n != 2 || !slices.Equal(buf, []int{3, 4}) {
+		t.Errorf("PopInto: got %d, %v; want 2, [3 4]", n, buf)
+	}
+	buf = make([]int, 10)
+	if n := q.PopInto(buf[:0]); n != 0 {
+		t.Errorf("PopInto(empty): got %d, want 0", n)
+	}
+	if n := q.PopInto(buf); n != 3 || !slices.Equal(buf[:n], []int{5, 6, 7}) {
+		t.Errorf("PopInto: got %d, %v; want 3, [5 6 7]", n, buf[:n])
+	}
+	if !q.IsEmpty() {
+		t.Errorf("Queue is not empty: %v", q.Slice())
+	}
+	if n := q.PopInto(buf); n != 0 {
+		t.Errorf("PopInto on empty queue: got %d, want 0", n)
+	}
+
+	// The queue continues to work normally after draining.
+	q.Add(8)
+	q.Add(9)
+	if got := q.Slice(); !slices.Equal(got, []int{8, 9}) {
+		t.Errorf("Slice: got %v, want [8 9]", got)
+	}
+	if got := q.Stats().Removed; got != 7 {
+		t.Errorf("Stats.Removed: got %d, want 7", got)
+	}
+}
+
+func TestStats(t *testing.T) {
+	q := queue.NewSize[int](4)
+	check := func(want queue.Stats) {
+		t.Helper()
+		if got := q.Stats(); got != want {
+			t.Errorf("Stats: got %+v, want %+v", got, want)
+		}
+	}
+	if got := q.Cap(); got != 4 {
+		t.Errorf("Cap: got %d, want 4", got)
+	}
+	check(queue.Stats{})
+
+	q.Add(1)
+	q.Add(2)
+	q.Push(3)
+	q.Pop()
+	check(queue.Stats{MaxLen: 3, Added: 3, Removed: 1})
+
+	for i := range 5 {
+		q.Add(i)
+	}
+	q.PopLast()
+	check(queue.Stats{MaxLen: 7, Added: 8, Removed: 2})
+	if got := q.Cap(); got < 7 {
+		t.Errorf("Cap: got %d, want at least 7", got)
+	}
+
+	// Removing from an empty queue does not count.
+	q.Clear()
+	q.Pop()
+	q.PopLast()
+	check(queue.Stats{MaxLen: 7, Added: 8, Removed: 2})
+	if got := q.Cap(); got != 0 {
+		t.Errorf("Cap after Clear: got %d, want 0", got)
+	}
+
+	q.Add(10)
+	q.ResetStats()
+	check(queue.Stats{MaxLen: 1})
 }
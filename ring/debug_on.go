@@ -0,0 +1,6 @@
+//go:build ringdebug
+
+package ring
+
+// debug enables consistency checks on ring operations.
+const debug = true
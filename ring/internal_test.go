@@ -0,0 +1,24 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/creachadair/mds/mtest"
+)
+
+func TestValidate(t *testing.T) {
+	validate[int](nil)
+	validate(Of(1))
+	validate(Of(1, 2, 3))
+
+	// Corrupt a ring by linking one of its elements into another ring without
+	// updating the back links.
+	r, s := Of(1, 2, 3), Of(4, 5)
+	r.next = s
+	mtest.MustPanic(t, func() { validate(r) })
+
+	// A broken back link is detected.
+	r = Of(1, 2, 3)
+	r.next.next.prev = r
+	mtest.MustPanic(t, func() { validate(r) })
+}
@@ -79,7 +79,15 @@ func Of[T any](vs ...T) *Ring[T] {
 //
 // In this case Join returns the ring [r2 ... ri] that was spliced out.  This
 // may be empty (nil) if there were no elements between r1 and s1.
+//
+// Use [Ring.Distinct] to check which case applies, or [Ring.SplitAt] to split
+// a ring at an offset.  When built with the "ringdebug" tag, Join checks that
+// the links of r and s are consistent, and panics if they are not.
 func (r *Ring[T]) Join(s *Ring[T]) *Ring[T] {
+	if debug {
+		validate(r)
+		validate(s)
+	}
 	if r == s || r.next == s {
 		return nil // same ring, no elements between r and s to remove
 	}
@@ -92,6 +100,40 @@ func (r *Ring[T]) Join(s *Ring[T]) *Ring[T] {
 	return rnext
 }
 
+// SplitAt detaches the elements at offsets n and after from r, and returns
+// them as a separate ring. After splitting, r contains its first n elements,
+// so that if r was [r1 ... rn ... rm], the result is [rn+1 ... rm] and r is
+// [r1 ... rn]. If n ≥ r.Len(), nothing is detached and SplitAt returns nil.
+//
+// SplitAt will panic if n ≤ 0 or r == nil, since r cannot be made empty.
+func (r *Ring[T]) SplitAt(n int) *Ring[T] {
+	if r == nil || n <= 0 {
+		panic("n out of range")
+	}
+	last := r.At(n - 1)
+	if last == nil || last.next == r {
+		return nil // n ≥ r.Len()
+	} else if last == r {
+		// Join(r) is a no-op here; detach r from the rest instead.
+		rest := r.next
+		r.Pop()
+		return rest
+	}
+	return last.Join(r)
+}
+
+// Distinct reports whether r and s belong to different rings. An empty ring
+// is distinct from every ring. This operation takes time proportional to the
+// size of the ring containing r.
+func (r *Ring[T]) Distinct(s *Ring[T]) bool {
+	if r == nil || s == nil {
+		return true
+	}
+	found := false
+	scan(r, func(cur *Ring[T]) bool { found = cur == s; return !found })
+	return !found
+}
+
 // Pop detaches r from its ring, leaving it linked only to itself.
 // It returns r to permit method chaining.
 func (r *Ring[T]) Pop() *Ring[T] {
@@ -184,4 +226,14 @@ func scan[T any](r *Ring[T], f func(*Ring[T]) bool) {
 	}
 }
 
+// validate checks that the links of r are consistent, and panics if not.
+func validate[T any](r *Ring[T]) {
+	scan(r, func(cur *Ring[T]) bool {
+		if cur.next == nil || cur.prev == nil || cur.next.prev != cur || cur.prev.next != cur {
+			panic(fmt.Sprintf("ring: inconsistent links at %v", cur))
+		}
+		return true
+	})
+}
+
 func newRing[T any]() *Ring[T] { r := new(Ring[T]); r.next = r; r.prev = r; return r }
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/creachadair/mds/internal/mdtest"
+	"github.com/creachadair/mds/mtest"
 	"github.com/creachadair/mds/ring"
 )
 
@@ -85,6 +86,51 @@ func TestRing(t *testing.T) {
 		rc(t, s, "dizzy", "after", "eating")
 	})
 
+	t.Run("SplitAt", func(t *testing.T) {
+		r := ring.Of(1, 2, 3, 4, 5)
+		rc(t, r.SplitAt(5))
+		rc(t, r.SplitAt(8))
+		rc(t, r, 1, 2, 3, 4, 5)
+
+		s := r.SplitAt(3)
+		rc(t, r, 1, 2, 3)
+		rc(t, s, 4, 5)
+		if !r.Distinct(s) {
+			t.Error("After SplitAt: rings should be distinct")
+		}
+		rc(t, r.SplitAt(1), 2, 3)
+		rc(t, r, 1)
+
+		mtest.MustPanic(t, func() { r.SplitAt(0) })
+		mtest.MustPanic(t, func() { (*ring.Ring[int])(nil).SplitAt(1) })
+	})
+
+	t.Run("Distinct", func(t *testing.T) {
+		r, s := ring.Of(1, 2, 3), ring.Of(4, 5)
+		var zero *ring.Ring[int]
+		for _, tc := range []struct {
+			a, b *ring.Ring[int]
+			want bool
+		}{
+			{zero, zero, true},
+			{r, zero, true},
+			{zero, r, true},
+			{r, r, false},
+			{r, r.Prev(), false},
+			{r.Next(), r, false},
+			{r, s, true},
+			{s.Next(), r.Next(), true},
+		} {
+			if got := tc.a.Distinct(tc.b); got != tc.want {
+				t.Errorf("%v.Distinct(%v): got %v, want %v", tc.a, tc.b, got, tc.want)
+			}
+		}
+		r.Join(s)
+		if r.Distinct(s) {
+			t.Error("After Join: rings should not be distinct")
+		}
+	})
+
 	t.Run("Peek", func(t *testing.T) {
 		r := ring.Of("kingdom", "phylum", "class", "order", "family", "genus", "species")
 		checkPeek := func(n int, want string, wantok bool) {
@@ -158,6 +158,42 @@ type Scanner struct {
 	cur bytes.Buffer
 	st  state
 	err error
+
+	keepSpans bool   // whether to record quotation spans
+	spans     []Span // spans for the current token
+	open      int    // start offset of the current quoted span
+}
+
+// Quoting identifies the quotation applied to a span of a token.
+type Quoting byte
+
+const (
+	Unquoted     Quoting = iota // no quotation
+	SingleQuoted                // enclosed in single quotes ('...')
+	DoubleQuoted                // enclosed in double quotes ("...")
+	Escaped                     // preceded by a backslash (\x)
+)
+
+var quotingStr = [...]string{
+	Unquoted:     "unquoted",
+	SingleQuoted: "single",
+	DoubleQuoted: "double",
+	Escaped:      "escaped",
+}
+
+func (q Quoting) String() string {
+	if int(q) < len(quotingStr) {
+		return quotingStr[q]
+	}
+	return "invalid"
+}
+
+// A Span records the location of a quoted region in the text of a token.  The
+// offsets are relative to the string reported by the Text method. A quoted
+// region may be empty (Pos == End) if the input contains an empty quotation.
+type Span struct {
+	Pos, End int     // the region is Text()[Pos:End]
+	Quote    Quoting // the quotation applied to the region
 }
 
 // NewScanner returns a Scanner that reads input from r.
@@ -176,6 +212,30 @@ func (s *Scanner) Reset(r io.Reader) {
 	s.cur.Reset()
 	s.st = stBreak
 	s.err = nil
+	s.spans = s.spans[:0]
+}
+
+// KeepSpans enables (if keep is true) or disables (if keep is false) the
+// recording of quotation spans for each token, and returns s.
+// When enabled, the Spans method reports the regions of the current token
+// that were single-quoted, double-quoted, or escaped in the input. This
+// permits a caller to reproduce the quotation structure of the original input.
+// Spans are not recorded by default.
+func (s *Scanner) KeepSpans(keep bool) *Scanner { s.keepSpans = keep; return s }
+
+// Spans returns the quoted regions of the current token, in order of their
+// position in the token. Any part of the token not covered by a span was
+// unquoted in the input. Spans returns nil unless recording is enabled by
+// KeepSpans. The slice returned is only valid until the next call to Next.
+//
+// An escaped character inside double quotes is reported as part of the
+// enclosing double-quoted span. If the current token has an unclosed
+// quotation, its span extends to the end of the token.
+func (s *Scanner) Spans() []Span {
+	if len(s.spans) == 0 {
+		return nil
+	}
+	return s.spans
 }
 
 // Next advances the scanner and reports whether there are any further tokens
@@ -185,6 +245,7 @@ func (s *Scanner) Next() bool {
 		return false
 	}
 	s.cur.Reset()
+	s.spans = s.spans[:0]
 	for {
 		c, err := s.buf.ReadByte()
 		s.err = err
@@ -193,6 +254,7 @@ func (s *Scanner) Next() bool {
 		} else if err != nil {
 			return false
 		}
+		prev := s.st
 		next := update[s.st][classOf[c]]
 		s.st = next.state
 		switch next.action {
@@ -203,14 +265,45 @@ func (s *Scanner) Next() bool {
 		case emit:
 			return true // s.cur has a complete token
 		case drop:
-			continue
 		default:
 			panic("unknown action")
 		}
+		if s.keepSpans {
+			s.markSpan(prev, next.state, next.action)
+		}
+	}
+	if s.keepSpans {
+		switch s.st {
+		case stSingle:
+			s.addSpan(s.open, SingleQuoted)
+		case stDouble, stDoubleQ:
+			s.addSpan(s.open, DoubleQuoted)
+		}
 	}
 	return s.st != stBreak
 }
 
+// markSpan updates the quotation spans of the current token for a transition
+// from state prev to state next, which performed the given action.
+func (s *Scanner) markSpan(prev, next state, act action) {
+	switch {
+	case next == stSingle && prev != stSingle, next == stDouble && prev != stDouble && prev != stDoubleQ:
+		s.open = s.cur.Len() // opening a quotation
+	case prev == stSingle && next == stWord:
+		s.addSpan(s.open, SingleQuoted)
+	case prev == stDouble && next == stWord:
+		s.addSpan(s.open, DoubleQuoted)
+	case (prev == stBreakQ || prev == stWordQ) && act == push:
+		s.addSpan(s.cur.Len()-1, Escaped)
+	}
+}
+
+// addSpan records a span of the current token from pos to the end of the
+// token, with the given quotation.
+func (s *Scanner) addSpan(pos int, q Quoting) {
+	s.spans = append(s.spans, Span{Pos: pos, End: s.cur.Len(), Quote: q})
+}
+
 // Text returns the text of the current token, or "" if there is none.
 func (s *Scanner) Text() string { return s.cur.String() }
 
@@ -227,6 +320,7 @@ func (s *Scanner) Complete() bool { return s.st == stBreak || s.st == stWord }
 func (s *Scanner) Rest() io.Reader {
 	s.st = stNone
 	s.cur.Reset()
+	s.spans = s.spans[:0]
 	s.err = io.EOF
 	return s.buf
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"slices"
 	"strings"
 	"testing"
 
@@ -115,6 +116,60 @@ func TestScannerSplit(t *testing.T) {
 	}
 }
 
+func TestScannerSpans(t *testing.T) {
+	type span = shell.Span
+	tests := []struct {
+		in    string
+		want  []string
+		spans [][]span
+	}{
+		{"", nil, nil},
+		{"a b", []string{"a", "b"}, [][]span{nil, nil}},
+		{`'a b'`, []string{"a b"}, [][]span{{{0, 3, shell.SingleQuoted}}}},
+		{`"a b"`, []string{"a b"}, [][]span{{{0, 3, shell.DoubleQuoted}}}},
+		{`a\ b`, []string{"a b"}, [][]span{{{1, 2, shell.Escaped}}}},
+		{`\\a`, []string{`\a`}, [][]span{{{0, 1, shell.Escaped}}}},
+		{"a''b", []string{"ab"}, [][]span{{{1, 1, shell.SingleQuoted}}}},
+		{`x'y'"z\"w"v`, []string{`xyz"wv`}, [][]span{{
+			{1, 2, shell.SingleQuoted},
+			{2, 5, shell.DoubleQuoted},
+		}}},
+		{`p "q r" s\\`, []string{"p", "q r", `s\`}, [][]span{
+			nil,
+			{{0, 3, shell.DoubleQuoted}},
+			{{1, 2, shell.Escaped}},
+		}},
+		{"a\\\nb", []string{"ab"}, [][]span{nil}},
+
+		// Unclosed quotations extend to the end of the token.
+		{`a 'b c`, []string{"a", "b c"}, [][]span{nil, {{0, 3, shell.SingleQuoted}}}},
+		{`"b \"`, []string{`b "`}, [][]span{{{0, 3, shell.DoubleQuoted}}}},
+	}
+	for _, test := range tests {
+		s := shell.NewScanner(strings.NewReader(test.in)).KeepSpans(true)
+		var got []string
+		var spans [][]span
+		for s.Next() {
+			got = append(got, s.Text())
+			spans = append(spans, slices.Clone(s.Spans()))
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("Scan %#q: tokens (-want, +got)\n%s", test.in, diff)
+		}
+		if diff := cmp.Diff(test.spans, spans); diff != "" {
+			t.Errorf("Scan %#q: spans (-want, +got)\n%s", test.in, diff)
+		}
+	}
+
+	// Without KeepSpans, no spans are reported.
+	s := shell.NewScanner(strings.NewReader(`'a' "b"`))
+	for s.Next() {
+		if sp := s.Spans(); sp != nil {
+			t.Errorf("Token %q: got spans %v, want none", s.Text(), sp)
+		}
+	}
+}
+
 func TestRoundTrip(t *testing.T) {
 	tests := [][]string{
 		nil,
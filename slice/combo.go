@@ -0,0 +1,132 @@
+package slice
+
+import "iter"
+
+// Permutations returns an iterator over all the permutations of vs.  If vs has
+// length n, the iterator yields n! slices. Each permutation is generated as it
+// is needed, using Heap's algorithm. The input is not modified; if vs is
+// empty, the iterator yields a single empty slice.
+//
+// The slice yielded by the iterator is reused between iterations, so the
+// caller must copy it to retain its contents after the iteration continues.
+func Permutations[T any, Slice ~[]T](vs Slice) iter.Seq[Slice] {
+	return func(yield func(Slice) bool) {
+		cur := make(Slice, len(vs))
+		copy(cur, vs)
+		if !yield(cur) {
+			return
+		}
+
+		// c[i] is the loop counter for position i in the recursive formulation
+		// of Heap's algorithm.
+		c := make([]int, len(cur))
+		for i := 1; i < len(cur); {
+			if c[i] < i {
+				if i%2 == 0 {
+					cur[0], cur[i] = cur[i], cur[0]
+				} else {
+					cur[c[i]], cur[i] = cur[i], cur[c[i]]
+				}
+				if !yield(cur) {
+					return
+				}
+				c[i]++
+				i = 1
+			} else {
+				c[i] = 0
+				i++
+			}
+		}
+	}
+}
+
+// Combinations returns an iterator over all the combinations of k elements
+// from vs, in lexicographic order of their offsets. Each combination preserves
+// the relative order of the elements in vs. If vs has length n and 0 ≤ k ≤ n,
+// the iterator yields n choose k slices, otherwise it yields nothing. The input
+// is not modified.
+//
+// The slice yielded by the iterator is reused between iterations, so the
+// caller must copy it to retain its contents after the iteration continues.
+func Combinations[T any, Slice ~[]T](vs Slice, k int) iter.Seq[Slice] {
+	return func(yield func(Slice) bool) {
+		if k < 0 || k > len(vs) {
+			return
+		}
+
+		// idx holds the offsets in vs of the current combination.
+		idx := make([]int, k)
+		cur := make(Slice, k)
+		for i := range idx {
+			idx[i] = i
+			cur[i] = vs[i]
+		}
+		for {
+			if !yield(cur) {
+				return
+			}
+
+			// Find the rightmost offset that can be advanced, advance it, and
+			// reset all the offsets following it.
+			i := k - 1
+			for i >= 0 && idx[i] == len(vs)-k+i {
+				i--
+			}
+			if i < 0 {
+				return // all done
+			}
+			idx[i]++
+			cur[i] = vs[idx[i]]
+			for j := i + 1; j < k; j++ {
+				idx[j] = idx[j-1] + 1
+				cur[j] = vs[idx[j]]
+			}
+		}
+	}
+}
+
+// Cartesian returns an iterator over the Cartesian product of vss. Each slice
+// yielded has one element from each of vss in order, and the slices are
+// yielded in lexicographic order of their offsets, with the last position
+// varying fastest. If vss has lengths n1, n2, ..., the iterator yields
+// n1·n2·... slices; in particular, if any of vss is empty it yields nothing,
+// and if vss is empty it yields a single empty slice. The inputs are not
+// modified.
+//
+// The slice yielded by the iterator is reused between iterations, so the
+// caller must copy it to retain its contents after the iteration continues.
+func Cartesian[T any, Slice ~[]T](vss ...Slice) iter.Seq[Slice] {
+	return func(yield func(Slice) bool) {
+		for _, vs := range vss {
+			if len(vs) == 0 {
+				return
+			}
+		}
+
+		// idx holds the offsets in each of vss of the current tuple.
+		idx := make([]int, len(vss))
+		cur := make(Slice, len(vss))
+		for i, vs := range vss {
+			cur[i] = vs[0]
+		}
+		for {
+			if !yield(cur) {
+				return
+			}
+
+			// Advance the rightmost position that is not at its end, and reset
+			// all the positions following it.
+			i := len(vss) - 1
+			for i >= 0 && idx[i] == len(vss[i])-1 {
+				idx[i] = 0
+				cur[i] = vss[i][0]
+				i--
+			}
+			if i < 0 {
+				return // all done
+			}
+			idx[i]++
+			cur[i] = vss[i][idx[i]]
+		}
+	}
+}
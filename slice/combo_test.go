@@ -0,0 +1,136 @@
+package slice_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/mds/mapset"
+	"github.com/creachadair/mds/slice"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestPermutations(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"", 1},
+		{"a", 1},
+		{"a b", 2},
+		{"a b c", 6},
+		{"a b c d", 24},
+		{"a b c d e", 120},
+	}
+	for _, tc := range tests {
+		input := strings.Fields(tc.input)
+		orig := slices.Clone(input)
+
+		seen := mapset.New[string]()
+		for p := range slice.Permutations(input) {
+			if diff := cmp.Diff(slices.Sorted(slices.Values(p)), orig, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("Permutation %q is not a permutation of %q", p, orig)
+			}
+			key := fmt.Sprint(p)
+			if seen.Has(key) {
+				t.Errorf("Duplicate permutation %q", p)
+			}
+			seen.Add(key)
+		}
+		if seen.Len() != tc.want {
+			t.Errorf("Permutations(%q): got %d, want %d", tc.input, seen.Len(), tc.want)
+		}
+		if !slices.Equal(input, orig) {
+			t.Errorf("Permutations modified its input: got %q, want %q", input, orig)
+		}
+	}
+
+	t.Run("Stop", func(t *testing.T) {
+		n := 0
+		for range slice.Permutations([]int{1, 2, 3, 4}) {
+			n++
+			if n == 5 {
+				break
+			}
+		}
+		if n != 5 {
+			t.Errorf("Got %d permutations, want 5", n)
+		}
+	})
+}
+
+func TestCombinations(t *testing.T) {
+	tests := []struct {
+		input string
+		k     int
+		want  []string
+	}{
+		{"", 0, []string{""}},
+		{"", 1, nil},
+		{"a b c", -1, nil},
+		{"a b c", 4, nil},
+		{"a b c", 0, []string{""}},
+		{"a b c", 1, []string{"a", "b", "c"}},
+		{"a b c", 2, []string{"a b", "a c", "b c"}},
+		{"a b c", 3, []string{"a b c"}},
+		{"a b c d", 2, []string{"a b", "a c", "a d", "b c", "b d", "c d"}},
+		{"a b c d e", 3, []string{
+			"a b c", "a b d", "a b e", "a c d", "a c e",
+			"a d e", "b c d", "b c e", "b d e", "c d e",
+		}},
+	}
+	for _, tc := range tests {
+		var got []string
+		for c := range slice.Combinations(strings.Fields(tc.input), tc.k) {
+			got = append(got, strings.Join(c, " "))
+		}
+		if diff := cmp.Diff(got, tc.want); diff != "" {
+			t.Errorf("Combinations(%q, %d) (-got, +want):\n%s", tc.input, tc.k, diff)
+		}
+	}
+}
+
+func TestCartesian(t *testing.T) {
+	tests := []struct {
+		input []string // each is a space-separated slice
+		want  []string
+	}{
+		{nil, []string{""}},
+		{[]string{""}, nil},
+		{[]string{"a b", ""}, nil},
+		{[]string{"a b c"}, []string{"a", "b", "c"}},
+		{[]string{"a b", "x y"}, []string{"a x", "a y", "b x", "b y"}},
+		{[]string{"a", "x y z", "1 2"}, []string{
+			"a x 1", "a x 2", "a y 1", "a y 2", "a z 1", "a z 2",
+		}},
+		{[]string{"a b", "x", "1 2"}, []string{"a x 1", "a x 2", "b x 1", "b x 2"}},
+	}
+	for _, tc := range tests {
+		var input [][]string
+		for _, s := range tc.input {
+			input = append(input, strings.Fields(s))
+		}
+		var got []string
+		for c := range slice.Cartesian(input...) {
+			got = append(got, strings.Join(c, " "))
+		}
+		if diff := cmp.Diff(got, tc.want); diff != "" {
+			t.Errorf("Cartesian(%q) (-got, +want):\n%s", tc.input, diff)
+		}
+	}
+
+	t.Run("Stop", func(t *testing.T) {
+		var n int
+		for range slice.Cartesian([]int{1, 2, 3}, []int{4, 5, 6}) {
+			n++
+			if n == 4 {
+				break
+			}
+		}
+		if n != 4 {
+			t.Errorf("Got %d tuples, want 4", n)
+		}
+	})
+}
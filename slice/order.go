@@ -0,0 +1,374 @@
+package slice
+
+import (
+	"math/bits"
+	"slices"
+
+	"github.com/creachadair/mds/heapq"
+)
+
+// TopK returns a slice of the k smallest elements of vs in non-decreasing
+// order by cmp. If len(vs) ≤ k, the result contains all the elements of vs.
+// To select the k largest elements, reverse the sense of cmp.  The input is
+// not modified.
+//
+// TopK takes time O(n·lg k) for an input of length n, and O(k) space.
+// TopK will panic if k < 0. If k == 0, TopK returns nil.
+func TopK[T any, Slice ~[]T](vs Slice, k int, cmp func(a, b T) int) Slice {
+	if k < 0 {
+		panic("k out of range")
+	} else if k == 0 || len(vs) == 0 {
+		return nil
+	}
+	k = min(k, len(vs))
+
+	// Maintain a max-heap of the k smallest elements seen so far, so that the
+	// largest of them is at the front to be replaced.
+	q := heapq.NewWithData(func(a, b T) int { return cmp(b, a) }, make([]T, 0, k))
+	for _, v := range vs {
+		if q.Len() < k {
+			q.Add(v)
+		} else if cmp(v, q.Front()) < 0 {
+			q.Pop()
+			q.Add(v)
+		}
+	}
+	out := make(Slice, q.Len())
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i], _ = q.Pop()
+	}
+	return out
+}
+
+// NthElement partially reorders vs in-place so that the element at offset n
+// is the one that would be there if vs were sorted by cmp. After the call,
+// every element before offset n compares less than or equal to vs[n], and
+// every element after offset n compares greater than or equal to vs[n].
+// Apart from that, the order of the elements is unspecified.
+//
+// NthElement takes expected time proportional to len(vs), and worst-case time
+// O(n·lg n). It does not allocate storage outside the slice.  NthElement will
+// panic if n < 0 or n ≥ len(vs).
+func NthElement[T any, Slice ~[]T](vs Slice, n int, cmp func(a, b T) int) {
+	if n < 0 || n >= len(vs) {
+		panic("index out of range")
+	}
+
+	// Introselect: Use quickselect, but if partitioning does not converge
+	// quickly enough, fall back to sorting the remaining range.
+	lo, hi := 0, len(vs)
+	budget := 2 * bits.Len(uint(len(vs)))
+	for hi-lo > 12 {
+		if budget == 0 {
+			slices.SortFunc(vs[lo:hi], cmp)
+			return
+		}
+		budget--
+
+		lt, gt := partition3(vs[lo:hi], cmp)
+		if n < lo+lt {
+			hi = lo + lt
+		} else if n >= lo+gt {
+			lo += gt
+		} else {
+			return // n is among the elements equal to the pivot
+		}
+	}
+
+	// Small ranges are handled by insertion sort.
+	for i := lo + 1; i < hi; i++ {
+		for j := i; j > lo && cmp(vs[j], vs[j-1]) < 0; j-- {
+			vs[j], vs[j-1] = vs[j-1], vs[j]
+		}
+	}
+}
+
+// partition3 rearranges vs around a pivot chosen by median-of-three into
+// three ranges, vs[:lt] less than the pivot, vs[lt:gt] equal to the pivot,
+// and vs[gt:] greater than the pivot, and returns lt and gt.
+// Precondition: len(vs) ≥ 3.
+func partition3[T any, Slice ~[]T](vs Slice, cmp func(a, b T) int) (lt, gt int) {
+	a, b, c := 0, len(vs)/2, len(vs)-1
+	if cmp(vs[b], vs[a]) < 0 {
+		a, b = b, a
+	}
+	if cmp(vs[c], vs[b]) < 0 {
+		b = c
+		if cmp(vs[b], vs[a]) < 0 {
+			b = a
+		}
+	}
+	pivot := vs[b]
+
+	// Invariant: vs[:lt] < pivot, vs[lt:i] == pivot, vs[gt:] > pivot.
+	lt, i, gt := 0, 0, len(vs)
+	for i < gt {
+		switch v := cmp(vs[i], pivot); {
+		case v < 0:
+			vs[lt], vs[i] = vs[i], vs[lt]
+			lt++
+			i++
+		case v > 0:
+			gt--
+			vs[i], vs[gt] = vs[gt], vs[i]
+		default:
+			i++
+		}
+	}
+	return lt, gt
+}
+
+// SearchRotated searches for target in vs, which must be sorted in
+// non-decreasing order by cmp and then rotated (as by [Rotate]) by some
+// unknown amount. If target is found, SearchRotated returns its offset and
+// true. Otherwise, it returns the offset at which target could be inserted to
+// preserve the order, and false. The result is unspecified if vs contains
+// equivalent elements on both sides of the rotation point.
+//
+// SearchRotated takes time O(lg n) for an input of length n.
+func SearchRotated[T any, Slice ~[]T](vs Slice, target T, cmp func(a, b T) int) (int, bool) {
+	if len(vs) == 0 {
+		return 0, false
+	}
+
+	// Find the rotation point p, the offset of the first element not greater
+	// than the last. The elements before p are all greater than those after.
+	last := vs[len(vs)-1]
+	lo, hi := 0, len(vs)-1
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if cmp(vs[mid], last) > 0 {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	if lo == 0 || cmp(target, last) <= 0 {
+		i, ok := slices.BinarySearchFunc(vs[lo:], target, cmp)
+		return lo + i, ok
+	}
+	return slices.BinarySearchFunc(vs[:lo], target, cmp)
+}
+
+// IsKSorted reports whether vs is k-sorted by cmp, meaning that no element of
+// vs is more than k positions later than its position in sorted order. A
+// k-sorted input can be sorted by [SortKSorted] with the same k.
+//
+// IsKSorted takes time O(n·lg k) for an input of length n, and O(k) space.
+// IsKSorted will panic if k < 0.
+func IsKSorted[T any, Slice ~[]T](vs Slice, k int, cmp func(a, b T) int) bool {
+	var prev T
+	ok := true
+	kSort(vs, k, cmp, func(i int, v T) bool {
+		ok = i == 0 || cmp(prev, v) <= 0
+		prev = v
+		return ok
+	})
+	return ok
+}
+
+// SortKSorted sorts vs in-place in non-decreasing order by cmp, given that vs
+// is k-sorted as defined by [IsKSorted]. If vs is not k-sorted, the elements
+// are permuted but the resulting order is unspecified. The sort is not stable.
+//
+// SortKSorted takes time O(n·lg k) for an input of length n, and O(k) space,
+// which is faster than a general sort when k is small.
+// SortKSorted will panic if k < 0.
+func SortKSorted[T any, Slice ~[]T](vs Slice, k int, cmp func(a, b T) int) {
+	kSort(vs, k, cmp, func(i int, v T) bool { vs[i] = v; return true })
+}
+
+// kSort visits the elements of vs in the order they leave a sliding window of
+// k+1 elements held in a min-heap ordered by cmp, calling f with the output
+// offset and value of each until f returns false. The output offset never
+// exceeds the offset of the next input to be read, so f may write vs[i].
+func kSort[T any, Slice ~[]T](vs Slice, k int, cmp func(a, b T) int, f func(int, T) bool) {
+	if k < 0 {
+		panic("k out of range")
+	}
+	q := heapq.NewWithData(cmp, make([]T, 0, min(k+1, len(vs))))
+	out := 0
+	for _, v := range vs {
+		q.Add(v)
+		if q.Len() > k {
+			next, _ := q.Pop()
+			if !f(out, next) {
+				return
+			}
+			out++
+		}
+	}
+	for q.Len() != 0 {
+		next, _ := q.Pop()
+		if !f(out, next) {
+			return
+		}
+		out++
+	}
+}
+
+// SortStable sorts vs in-place in non-decreasing order by the given comparison
+// functions, preserving the relative order of equivalent elements. The
+// comparison functions have the same form used by other packages in this
+// module (e.g., heapq, stree, omap). Elements are ordered by the first
+// function, with ties broken by each subsequent function in turn.
+//
+// SortStable will panic if no comparison functions are given.
+func SortStable[T any, Slice ~[]T](vs Slice, cmps ...func(a, b T) int) {
+	slices.SortStableFunc(vs, chainCompare(cmps))
+}
+
+// IsSortedBy reports whether vs is sorted in non-decreasing order by the given
+// comparison functions, as defined by [SortStable].
+//
+// IsSortedBy will panic if no comparison functions are given.
+func IsSortedBy[T any, Slice ~[]T](vs Slice, cmps ...func(a, b T) int) bool {
+	return slices.IsSortedFunc(vs, chainCompare(cmps))
+}
+
+// MinMax returns the minimum and maximum elements of vs as ordered by cmp,
+// found in a single pass over the input. If several elements are minimal or
+// maximal, the first of each is returned. If vs is empty, MinMax returns zero
+// values and ok == false.
+func MinMax[T any, Slice ~[]T](vs Slice, cmp func(a, b T) int) (lo, hi T, ok bool) {
+	i, j := argMinMax(vs, cmp)
+	if i < 0 {
+		return
+	}
+	return vs[i], vs[j], true
+}
+
+// ArgMin returns the offset of the first minimum element of vs as ordered by
+// cmp. If vs is empty, ArgMin returns -1.
+func ArgMin[T any, Slice ~[]T](vs Slice, cmp func(a, b T) int) int {
+	i, _ := argMinMax(vs, cmp)
+	return i
+}
+
+// ArgMax returns the offset of the first maximum element of vs as ordered by
+// cmp. If vs is empty, ArgMax returns -1.
+func ArgMax[T any, Slice ~[]T](vs Slice, cmp func(a, b T) int) int {
+	_, j := argMinMax(vs, cmp)
+	return j
+}
+
+// ArgSort returns a permutation of the offsets of vs that sorts vs by cmp, so
+// that vs[p[0]], vs[p[1]], ... are in order. The sort is stable: offsets of
+// elements that compare equal are in increasing order. The input is not
+// modified. If vs is empty, ArgSort returns nil.
+//
+// Use ApplyPermutation to reorder vs, or any slice parallel to it, by the
+// result. For example, to sort names and ages together by age:
+//
+//	p := slice.ArgSort(ages, cmp.Compare)
+//	slice.ApplyPermutation(ages, p)
+//	slice.ApplyPermutation(names, p)
+func ArgSort[T any, Slice ~[]T](vs Slice, cmp func(a, b T) int) []int {
+	if len(vs) == 0 {
+		return nil
+	}
+	p := make([]int, len(vs))
+	for i := range p {
+		p[i] = i
+	}
+	slices.SortStableFunc(p, func(i, j int) int { return cmp(vs[i], vs[j]) })
+	return p
+}
+
+// ApplyPermutation reorders the elements of vs in-place so that the element
+// at offset i of the result is the element at offset p[i] of the input, as
+// for the permutation returned by ArgSort. It panics if len(p) != len(vs), or
+// if p is not a permutation of the offsets of vs.
+//
+// ApplyPermutation takes time proportional to len(vs) but does not allocate
+// storage. It temporarily modifies p while running, but restores it before
+// returning.
+func ApplyPermutation[T any, Slice ~[]T](vs Slice, p []int) {
+	if len(p) != len(vs) {
+		panic("permutation length mismatch")
+	}
+	for _, k := range p {
+		if k < 0 || k >= len(p) {
+			panic("invalid permutation")
+		}
+	}
+
+	// Mark each target offset by complementing its entry. An offset that is
+	// already marked means p is not a permutation. This leaves all entries
+	// marked.
+	for i := range p {
+		k := p[i]
+		if k < 0 {
+			k = ^k
+		}
+		if p[k] < 0 {
+			unmark(p)
+			panic("invalid permutation")
+		}
+		p[k] = ^p[k]
+	}
+
+	// Chase the cycles of the permutation, unmarking entries as we go, so that
+	// marked entries indicate offsets not yet visited. Each element is moved
+	// only once.
+	for i := range p {
+		if p[i] >= 0 {
+			continue // already visited
+		}
+		cur, j := vs[i], i
+		for {
+			k := ^p[j]
+			p[j] = k
+			if k == i {
+				vs[j] = cur
+				break
+			}
+			vs[j] = vs[k]
+			j = k
+		}
+	}
+}
+
+// unmark restores any complemented entries of p.
+func unmark(p []int) {
+	for i, k := range p {
+		if k < 0 {
+			p[i] = ^k
+		}
+	}
+}
+
+// argMinMax returns the offsets of the first minimum and maximum elements of
+// vs, or -1, -1 if vs is empty.
+func argMinMax[T any, Slice ~[]T](vs Slice, cmp func(a, b T) int) (lo, hi int) {
+	if len(vs) == 0 {
+		return -1, -1
+	}
+	for i := 1; i < len(vs); i++ {
+		if cmp(vs[i], vs[lo]) < 0 {
+			lo = i
+		} else if cmp(vs[i], vs[hi]) > 0 {
+			hi = i
+		}
+	}
+	return lo, hi
+}
+
+// chainCompare returns a comparison function that orders values by the first
+// of cmps, breaking ties by each subsequent function in turn.
+func chainCompare[T any](cmps []func(a, b T) int) func(a, b T) int {
+	switch len(cmps) {
+	case 0:
+		panic("no comparison functions")
+	case 1:
+		return cmps[0]
+	}
+	return func(a, b T) int {
+		for _, cmp := range cmps {
+			if c := cmp(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+}
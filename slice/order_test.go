@@ -0,0 +1,342 @@
+package slice_test
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/creachadair/mds/mtest"
+	"github.com/creachadair/mds/slice"
+	diff "github.com/google/go-cmp/cmp"
+)
+
+func TestTopK(t *testing.T) {
+	input := []int{8, 6, 7, 5, 3, 0, 9, 1, 6}
+	tests := []struct {
+		k    int
+		want []int
+	}{
+		{0, nil},
+		{1, []int{0}},
+		{2, []int{0, 1}},
+		{4, []int{0, 1, 3, 5}},
+		{6, []int{0, 1, 3, 5, 6, 6}},
+		{9, []int{0, 1, 3, 5, 6, 6, 7, 8, 9}},
+		{20, []int{0, 1, 3, 5, 6, 6, 7, 8, 9}},
+	}
+	orig := slices.Clone(input)
+	for _, tc := range tests {
+		got := slice.TopK(input, tc.k, cmp.Compare[int])
+		if diff := diff.Diff(got, tc.want); diff != "" {
+			t.Errorf("TopK(%v, %d) (-got, +want):\n%s", input, tc.k, diff)
+		}
+	}
+	if !slices.Equal(input, orig) {
+		t.Errorf("TopK modified its input: got %v, want %v", input, orig)
+	}
+
+	got := slice.TopK(input, 3, func(a, b int) int { return cmp.Compare(b, a) })
+	if diff := diff.Diff(got, []int{9, 8, 7}); diff != "" {
+		t.Errorf("TopK largest (-got, +want):\n%s", diff)
+	}
+	if got := slice.TopK([]int(nil), 5, cmp.Compare[int]); got != nil {
+		t.Errorf("TopK(nil, 5): got %v, want nil", got)
+	}
+
+	mtest.MustPanic(t, func() { slice.TopK(input, -1, cmp.Compare[int]) })
+}
+
+func TestNthElement(t *testing.T) {
+	check := func(t *testing.T, input []int) {
+		t.Helper()
+		want := slices.Sorted(slices.Values(input))
+		for n := range input {
+			vs := slices.Clone(input)
+			slice.NthElement(vs, n, cmp.Compare[int])
+			if vs[n] != want[n] {
+				t.Errorf("NthElement(%d): got %d, want %d", n, vs[n], want[n])
+			}
+			for i, v := range vs {
+				if (i < n && v > vs[n]) || (i > n && v < vs[n]) {
+					t.Errorf("NthElement(%d): element %d = %d out of place (pivot %d)", n, i, v, vs[n])
+				}
+			}
+			if got := slices.Sorted(slices.Values(vs)); !slices.Equal(got, want) {
+				t.Errorf("NthElement(%d): contents changed: got %v, want %v", n, got, want)
+			}
+		}
+	}
+
+	t.Run("Small", func(t *testing.T) {
+		check(t, []int{1})
+		check(t, []int{2, 1})
+		check(t, []int{8, 6, 7, 5, 3, 0, 9})
+	})
+	t.Run("Sorted", func(t *testing.T) {
+		vs := make([]int, 100)
+		for i := range vs {
+			vs[i] = i
+		}
+		check(t, vs)
+		slices.Reverse(vs)
+		check(t, vs)
+	})
+	t.Run("Duplicates", func(t *testing.T) {
+		vs := make([]int, 100)
+		for i := range vs {
+			vs[i] = i % 3
+		}
+		check(t, vs)
+	})
+	t.Run("Random", func(t *testing.T) {
+		rng := rand.New(rand.NewPCG(5, 6))
+		for range 10 {
+			vs := make([]int, 50+rng.IntN(200))
+			for i := range vs {
+				vs[i] = rng.IntN(1000)
+			}
+			check(t, vs)
+		}
+	})
+
+	mtest.MustPanic(t, func() { slice.NthElement([]int(nil), 0, cmp.Compare[int]) })
+	mtest.MustPanic(t, func() { slice.NthElement([]int{1, 2}, 2, cmp.Compare[int]) })
+	mtest.MustPanic(t, func() { slice.NthElement([]int{1, 2}, -1, cmp.Compare[int]) })
+}
+
+func TestSortStable(t *testing.T) {
+	type item struct {
+		Name string
+		Age  int
+		Tag  int // records input order
+	}
+	byName := func(a, b item) int { return cmp.Compare(a.Name, b.Name) }
+	byAge := func(a, b item) int { return cmp.Compare(a.Age, b.Age) }
+
+	input := []item{
+		{"carol", 30, 0}, {"alice", 25, 1}, {"bob", 30, 2},
+		{"alice", 20, 3}, {"bob", 30, 4}, {"alice", 25, 5},
+	}
+
+	t.Run("Single", func(t *testing.T) {
+		vs := slices.Clone(input)
+		if slice.IsSortedBy(vs, byName) {
+			t.Error("IsSortedBy: input should not be sorted")
+		}
+		slice.SortStable(vs, byName)
+		if !slice.IsSortedBy(vs, byName) {
+			t.Errorf("IsSortedBy: result should be sorted: %v", vs)
+		}
+		want := []item{
+			{"alice", 25, 1}, {"alice", 20, 3}, {"alice", 25, 5},
+			{"bob", 30, 2}, {"bob", 30, 4}, {"carol", 30, 0},
+		}
+		if diff := diff.Diff(vs, want); diff != "" {
+			t.Errorf("SortStable (-got, +want):\n%s", diff)
+		}
+	})
+
+	t.Run("Chain", func(t *testing.T) {
+		vs := slices.Clone(input)
+		slice.SortStable(vs, byAge, byName)
+		if !slice.IsSortedBy(vs, byAge, byName) {
+			t.Errorf("IsSortedBy: result should be sorted: %v", vs)
+		}
+		want := []item{
+			{"alice", 20, 3}, {"alice", 25, 1}, {"alice", 25, 5},
+			{"bob", 30, 2}, {"bob", 30, 4}, {"carol", 30, 0},
+		}
+		if diff := diff.Diff(vs, want); diff != "" {
+			t.Errorf("SortStable (-got, +want):\n%s", diff)
+		}
+	})
+
+	mtest.MustPanic(t, func() { slice.SortStable(input) })
+	mtest.MustPanic(t, func() { slice.IsSortedBy(input) })
+}
+
+func TestArgSort(t *testing.T) {
+	if got := slice.ArgSort([]int(nil), cmp.Compare); got != nil {
+		t.Errorf("ArgSort(nil): got %v, want nil", got)
+	}
+
+	ages := []int{30, 25, 30, 20, 25}
+	names := []string{"carol", "alice", "bob", "dave", "eve"}
+	p := slice.ArgSort(ages, cmp.Compare)
+	if diff := diff.Diff(p, []int{3, 1, 4, 0, 2}); diff != "" {
+		t.Errorf("ArgSort (-got, +want):\n%s", diff)
+	}
+	if diff := diff.Diff(ages, []int{30, 25, 30, 20, 25}); diff != "" {
+		t.Errorf("ArgSort modified its input (-got, +want):\n%s", diff)
+	}
+
+	slice.ApplyPermutation(ages, p)
+	slice.ApplyPermutation(names, p)
+	if diff := diff.Diff(ages, []int{20, 25, 25, 30, 30}); diff != "" {
+		t.Errorf("Ages (-got, +want):\n%s", diff)
+	}
+	if diff := diff.Diff(names, []string{"dave", "alice", "eve", "carol", "bob"}); diff != "" {
+		t.Errorf("Names (-got, +want):\n%s", diff)
+	}
+	if diff := diff.Diff(p, []int{3, 1, 4, 0, 2}); diff != "" {
+		t.Errorf("ApplyPermutation modified p (-got, +want):\n%s", diff)
+	}
+
+	t.Run("Random", func(t *testing.T) {
+		for n := range 50 {
+			vs := make([]int, n)
+			for i := range vs {
+				vs[i] = rand.IntN(10)
+			}
+			p := slice.ArgSort(vs, cmp.Compare)
+			want := slices.Clone(vs)
+			slices.Sort(want)
+			slice.ApplyPermutation(vs, p)
+			if !slices.Equal(vs, want) {
+				t.Errorf("ApplyPermutation(%v): got %v, want %v", p, vs, want)
+			}
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		vs := []string{"a", "b", "c"}
+		for _, p := range [][]int{{0, 1}, {0, 1, 3}, {0, 1, -1}, {0, 1, 1}, {2, 2, 0}} {
+			orig := slices.Clone(p)
+			mtest.MustPanic(t, func() { slice.ApplyPermutation(vs, p) })
+			if !slices.Equal(p, orig) {
+				t.Errorf("ApplyPermutation modified invalid p: got %v, want %v", p, orig)
+			}
+		}
+		if diff := diff.Diff(vs, []string{"a", "b", "c"}); diff != "" {
+			t.Errorf("ApplyPermutation modified vs (-got, +want):\n%s", diff)
+		}
+	})
+}
+
+func TestMinMax(t *testing.T) {
+	type pair struct {
+		Key  int
+		Name string
+	}
+	byKey := func(a, b pair) int { return cmp.Compare(a.Key, b.Key) }
+	tests := []struct {
+		input          []pair
+		wantLo, wantHi int // offsets, -1 for none
+	}{
+		{nil, -1, -1},
+		{[]pair{{1, "a"}}, 0, 0},
+		{[]pair{{1, "a"}, {1, "b"}}, 0, 0},
+		{[]pair{{2, "a"}, {1, "b"}, {3, "c"}}, 1, 2},
+		{[]pair{{3, "a"}, {1, "b"}, {3, "c"}, {1, "d"}}, 1, 0},
+		{[]pair{{5, "a"}, {4, "b"}, {3, "c"}, {2, "d"}}, 3, 0},
+	}
+	for _, tc := range tests {
+		if got := slice.ArgMin(tc.input, byKey); got != tc.wantLo {
+			t.Errorf("ArgMin(%v): got %d, want %d", tc.input, got, tc.wantLo)
+		}
+		if got := slice.ArgMax(tc.input, byKey); got != tc.wantHi {
+			t.Errorf("ArgMax(%v): got %d, want %d", tc.input, got, tc.wantHi)
+		}
+		lo, hi, ok := slice.MinMax(tc.input, byKey)
+		if tc.wantLo < 0 {
+			if ok || lo != (pair{}) || hi != (pair{}) {
+				t.Errorf("MinMax(%v): got (%v, %v, %v), want zero values", tc.input, lo, hi, ok)
+			}
+		} else if !ok || lo != tc.input[tc.wantLo] || hi != tc.input[tc.wantHi] {
+			t.Errorf("MinMax(%v): got (%v, %v, %v), want (%v, %v, true)",
+				tc.input, lo, hi, ok, tc.input[tc.wantLo], tc.input[tc.wantHi])
+		}
+	}
+}
+
+func TestSearchRotated(t *testing.T) {
+	sorted := []int{1, 3, 5, 7, 9, 11}
+	for k := range len(sorted) {
+		vs := slices.Clone(sorted)
+		slice.Rotate(vs, k)
+		for target := 0; target <= 12; target++ {
+			i, ok := slice.SearchRotated(vs, target, cmp.Compare[int])
+			want := slices.Contains(vs, target)
+			if ok != want {
+				t.Errorf("SearchRotated(%v, %d): got %v, want %v", vs, target, ok, want)
+				continue
+			}
+			if ok {
+				if vs[i] != target {
+					t.Errorf("SearchRotated(%v, %d): got offset %d (%d)", vs, target, i, vs[i])
+				}
+				continue
+			}
+
+			// Inserting the target at the reported offset should give a
+			// rotation of a sorted slice.
+			ins := slices.Insert(slices.Clone(vs), i, target)
+			p := slice.ArgMin(ins, cmp.Compare[int])
+			slice.Rotate(ins, -p)
+			if !slices.IsSorted(ins) {
+				t.Errorf("SearchRotated(%v, %d): insert at %d is not rotated-sorted", vs, target, i)
+			}
+		}
+	}
+	if i, ok := slice.SearchRotated([]int(nil), 1, cmp.Compare[int]); i != 0 || ok {
+		t.Errorf("SearchRotated(nil, 1): got %d, %v; want 0, false", i, ok)
+	}
+}
+
+func TestKSorted(t *testing.T) {
+	tests := []struct {
+		input []int
+		k     int
+		want  bool
+	}{
+		{nil, 0, true},
+		{[]int{1, 2, 3}, 0, true},
+		{[]int{2, 1, 3}, 0, false},
+		{[]int{2, 1, 3}, 1, true},
+		{[]int{2, 3, 1}, 1, false},
+		{[]int{2, 3, 1}, 2, true},
+		{[]int{5, 1, 2, 3, 4}, 1, true}, // later elements move back only 1
+		{[]int{2, 3, 4, 5, 1}, 3, false},
+		{[]int{2, 3, 4, 5, 1}, 4, true},
+		{[]int{3, 1, 2, 6, 4, 5, 9, 7, 8}, 2, true},
+		{[]int{1, 3, 4, 2, 5}, 1, false},
+	}
+	for _, tc := range tests {
+		if got := slice.IsKSorted(tc.input, tc.k, cmp.Compare[int]); got != tc.want {
+			t.Errorf("IsKSorted(%v, %d): got %v, want %v", tc.input, tc.k, got, tc.want)
+		}
+		if tc.want {
+			got := slices.Clone(tc.input)
+			slice.SortKSorted(got, tc.k, cmp.Compare[int])
+			if !slices.IsSorted(got) {
+				t.Errorf("SortKSorted(%v, %d): got %v, not sorted", tc.input, tc.k, got)
+			}
+		}
+	}
+
+	// Displace each element of a sorted slice by at most k positions.
+	const k = 5
+	rng := rand.New(rand.NewPCG(1, 2))
+	vs := make([]int, 500)
+	for i := range vs {
+		vs[i] = i
+	}
+	for i := 0; i < len(vs); i += k + 1 {
+		blk := vs[i:min(i+k+1, len(vs))]
+		rng.Shuffle(len(blk), func(i, j int) { blk[i], blk[j] = blk[j], blk[i] })
+	}
+	if !slice.IsKSorted(vs, k, cmp.Compare[int]) {
+		t.Errorf("IsKSorted(%d): got false, want true", k)
+	}
+	if slice.IsKSorted(vs, 0, cmp.Compare[int]) {
+		t.Error("IsKSorted(0): got true, want false")
+	}
+	slice.SortKSorted(vs, k, cmp.Compare[int])
+	if !slices.IsSorted(vs) {
+		t.Errorf("SortKSorted(%d): result is not sorted: %v", k, vs)
+	}
+
+	mtest.MustPanic(t, func() { slice.IsKSorted(vs, -1, cmp.Compare[int]) })
+	mtest.MustPanic(t, func() { slice.SortKSorted(vs, -1, cmp.Compare[int]) })
+}
@@ -0,0 +1,72 @@
+package slice
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// ParallelMap returns a slice of the results of applying f to each element of
+// vs, in the same order as the input, using up to n goroutines concurrently.
+// If n == 0, one goroutine per element is used. ParallelMap returns nil if vs
+// is empty, and will panic if n < 0.
+//
+// The calls to f may occur in any order, and f must be safe for concurrent
+// use by multiple goroutines.
+func ParallelMap[T, U any, Slice ~[]T](vs Slice, n int, f func(T) U) []U {
+	out, _ := ParallelMapContext(context.Background(), vs, n,
+		func(_ context.Context, v T) (U, error) { return f(v), nil })
+	return out
+}
+
+// ParallelMapContext returns a slice of the results of applying f to each
+// element of vs, in the same order as the input, using up to n goroutines
+// concurrently. If n == 0, one goroutine per element is used.
+// ParallelMapContext will panic if n < 0.
+//
+// If any call to f reports an error, or if ctx ends, no further calls to f are
+// started, the context passed to calls still in progress is cancelled, and
+// ParallelMapContext returns nil and the first error reported. Otherwise it
+// returns the results, or nil if vs is empty.
+//
+// The calls to f may occur in any order, and f must be safe for concurrent
+// use by multiple goroutines.
+func ParallelMapContext[T, U any, Slice ~[]T](ctx context.Context, vs Slice, n int, f func(context.Context, T) (U, error)) ([]U, error) {
+	if n < 0 {
+		panic("n out of range")
+	} else if n == 0 || n > len(vs) {
+		n = len(vs)
+	}
+	if len(vs) == 0 {
+		return nil, ctx.Err()
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	out := make([]U, len(vs))
+	var next atomic.Int64 // the next unclaimed offset of vs
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				i := int(next.Add(1) - 1)
+				if i >= len(vs) {
+					return
+				}
+				u, err := f(ctx, vs[i])
+				if err != nil {
+					cancel(err) // only the first cause is retained
+					return
+				}
+				out[i] = u
+			}
+		}()
+	}
+	wg.Wait()
+	if err := context.Cause(ctx); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,104 @@
+package slice_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/creachadair/mds/mtest"
+	"github.com/creachadair/mds/slice"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParallelMap(t *testing.T) {
+	input := make([]int, 100)
+	want := make([]string, len(input))
+	for i := range input {
+		input[i] = i + 1
+		want[i] = strconv.Itoa(i + 1)
+	}
+
+	for _, n := range []int{0, 1, 3, 100, 500} {
+		var active, peak atomic.Int32
+		got := slice.ParallelMap(input, n, func(v int) string {
+			cur := active.Add(1)
+			defer active.Add(-1)
+			for {
+				old := peak.Load()
+				if cur <= old || peak.CompareAndSwap(old, cur) {
+					break
+				}
+			}
+			return strconv.Itoa(v)
+		})
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Errorf("ParallelMap n=%d (-got, +want):\n%s", n, diff)
+		}
+		if p := int(peak.Load()); n > 0 && p > n {
+			t.Errorf("ParallelMap n=%d: got %d concurrent calls", n, p)
+		}
+	}
+
+	if got := slice.ParallelMap([]int(nil), 4, strconv.Itoa); got != nil {
+		t.Errorf("ParallelMap(nil): got %v, want nil", got)
+	}
+	mtest.MustPanic(t, func() { slice.ParallelMap(input, -1, strconv.Itoa) })
+}
+
+func TestParallelMapContext(t *testing.T) {
+	input := make([]int, 1000)
+	for i := range input {
+		input[i] = i
+	}
+	t.Run("OK", func(t *testing.T) {
+		got, err := slice.ParallelMapContext(context.Background(), input, 8,
+			func(_ context.Context, v int) (int, error) { return 2 * v, nil })
+		if err != nil {
+			t.Fatalf("ParallelMapContext: unexpected error: %v", err)
+		}
+		for i, v := range got {
+			if v != 2*i {
+				t.Errorf("Result %d: got %d, want %d", i, v, 2*i)
+			}
+		}
+	})
+	t.Run("Error", func(t *testing.T) {
+		bad := errors.New("bad")
+		var calls atomic.Int32
+		got, err := slice.ParallelMapContext(context.Background(), input, 4,
+			func(_ context.Context, v int) (int, error) {
+				calls.Add(1)
+				if v == 10 {
+					return 0, bad
+				}
+				return v, nil
+			})
+		if !errors.Is(err, bad) {
+			t.Errorf("ParallelMapContext: got error %v, want %v", err, bad)
+		}
+		if got != nil {
+			t.Errorf("ParallelMapContext: got %d results, want nil", len(got))
+		}
+		if n := int(calls.Load()); n == len(input) {
+			t.Errorf("ParallelMapContext: got %d calls, want fewer", n)
+		}
+	})
+	t.Run("Cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		got, err := slice.ParallelMapContext(ctx, input, 2,
+			func(ctx context.Context, v int) (int, error) {
+				if v == 5 {
+					cancel()
+				}
+				return v, ctx.Err()
+			})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("ParallelMapContext: got error %v, want %v", err, context.Canceled)
+		}
+		if got != nil {
+			t.Errorf("ParallelMapContext: got %d results, want nil", len(got))
+		}
+	})
+}
@@ -2,7 +2,10 @@
 package slice
 
 import (
+	"fmt"
 	"iter"
+	"math/rand/v2"
+	"slices"
 )
 
 // Partition rearranges the elements of vs in-place so that all the elements v
@@ -74,6 +77,53 @@ func Partition[T any](vs []T, keep func(T) bool) []T {
 	return vs[:i:i]
 }
 
+// PartitionStable rearranges the elements of vs in-place so that all the
+// elements v for which keep(v) is true precede all those for which it is
+// false, and returns the kept and unkept portions of vs.  Unlike Partition,
+// the input order of both the kept and the unkept elements is preserved.
+// For example, given the input:
+//
+//	[6, 1, 3, 2, 8, 4, 5]
+//
+// and
+//
+//	func keep(v int) bool { return v%2 == 0 }
+//
+// after partition vs looks like:
+//
+//	[6, 2, 8, 4, 1, 3, 5]
+//
+// and the returned slices are [6, 2, 8, 4] and [1, 3, 5].
+//
+// PartitionStable calls keep exactly once for each element, and takes time
+// proportional to len(vs).  It allocates at most one auxiliary buffer, whose
+// length is at most the number of unkept elements. The capacity of the kept
+// slice is clipped to its length.
+func PartitionStable[T any, Slice ~[]T](vs Slice, keep func(T) bool) (kept, unkept Slice) {
+	// Skip the prefix of elements that are already in place.
+	i := 0
+	for i < len(vs) && keep(vs[i]) {
+		i++
+	}
+
+	// Invariant: Everything left of i is kept, and buf holds the unkept
+	// elements seen so far, in order.
+	var buf Slice
+	for j := i; j < len(vs); j++ {
+		if keep(vs[j]) {
+			vs[i] = vs[j]
+			i++
+		} else {
+			if buf == nil {
+				buf = make(Slice, 0, len(vs)-j)
+			}
+			buf = append(buf, vs[j])
+		}
+	}
+	copy(vs[i:], buf)
+	return vs[:i:i], vs[i:]
+}
+
 // Zero sets all the elements of vs to their zero value.
 func Zero[T any, Slice ~[]T](vs Slice) {
 	var zero T
@@ -129,6 +179,51 @@ func PtrAt[T any, Slice ~[]T](ss Slice, i int) *T {
 	return nil
 }
 
+// Range returns the subslice of vs from offset i up to but not including
+// offset j. As with [At], negative offsets count backward from the end of the
+// slice. Unlike an ordinary slice expression, Range does not panic if the
+// offsets are out of range: Offsets before the start or after the end of vs
+// are clipped to the nearest end, and if i ≥ j after clipping, the result is
+// empty. For example:
+//
+//	Range(vs, 1, -1)  // all but the first and last elements
+//	Range(vs, -3, 10) // the last 3 elements, or all of vs if it is shorter
+func Range[T any, Slice ~[]T](vs Slice, i, j int) Slice {
+	i, j = clipIndex(i, len(vs)), clipIndex(j, len(vs))
+	if i > j {
+		j = i
+	}
+	return vs[i:j]
+}
+
+// clipIndex returns the offset in a slice of length n corresponding to i,
+// counting negative offsets from the end, and clipped to the range 0..n.
+func clipIndex(i, n int) int {
+	if i < 0 {
+		i += n
+	}
+	return min(max(i, 0), n)
+}
+
+// SwapRemove removes the element of vs at offset i by replacing it with the
+// last element of vs, and returns the shortened slice and the value removed.
+// Negative offsets count backward from the end of the slice. This takes
+// constant time, but does not preserve the order of the remaining elements.
+// The vacated element at the end of vs is set to its zero value.
+//
+// If i is out of range, SwapRemove will panic.
+func SwapRemove[T any, Slice ~[]T](vs Slice, i int) (Slice, T) {
+	pos, ok := indexCheck(i, len(vs))
+	if !ok {
+		panic("index out of range")
+	}
+	out, last := vs[pos], len(vs)-1
+	vs[pos] = vs[last]
+	var zero T
+	vs[last] = zero
+	return vs[:last], out
+}
+
 // MatchingKeys returns an iterator over the keys k of m for which f(m[k]) is
 // true.  The results are delivered in arbitrary order.
 func MatchingKeys[T comparable, U any](m map[T]U, f func(U) bool) iter.Seq[T] {
@@ -143,6 +238,59 @@ func MatchingKeys[T comparable, U any](m map[T]U, f func(U) bool) iter.Seq[T] {
 	}
 }
 
+// Frequency returns a map from each distinct element of vs to the number of
+// times it occurs in vs. The result is never nil, even if vs is empty.
+func Frequency[T comparable, Slice ~[]T](vs Slice) map[T]int {
+	out := make(map[T]int)
+	for _, v := range vs {
+		out[v]++
+	}
+	return out
+}
+
+// FrequencyFunc returns a map from each distinct key of the elements of vs to
+// the number of elements of vs having that key. The key of an element v is
+// key(v). The result is never nil, even if vs is empty.
+func FrequencyFunc[T any, K comparable, Slice ~[]T](vs Slice, key func(T) K) map[K]int {
+	out := make(map[K]int)
+	for _, v := range vs {
+		out[key(v)]++
+	}
+	return out
+}
+
+// EqualUnordered reports whether as and bs contain the same elements with
+// the same multiplicities, regardless of their order.
+func EqualUnordered[T comparable, Slice ~[]T](as, bs Slice) bool {
+	if len(as) != len(bs) {
+		return false
+	}
+	count := Frequency(as)
+	for _, b := range bs {
+		if count[b] == 0 {
+			return false
+		}
+		count[b]--
+	}
+	return true
+}
+
+// EqualUnorderedFunc reports whether as and bs contain the same elements with
+// the same multiplicities, regardless of their order, using cmp to order and
+// compare elements. The inputs are not modified.
+//
+// EqualUnorderedFunc sorts copies of its inputs, so it takes O(n lg n) time
+// and O(n) space for inputs of length n.
+func EqualUnorderedFunc[T any, Slice ~[]T](as, bs Slice, cmp func(a, b T) int) bool {
+	if len(as) != len(bs) {
+		return false
+	}
+	sa, sb := slices.Clone(as), slices.Clone(bs)
+	slices.SortFunc(sa, cmp)
+	slices.SortFunc(sb, cmp)
+	return slices.EqualFunc(sa, sb, func(a, b T) bool { return cmp(a, b) == 0 })
+}
+
 // Rotate permutes the elements of ss in-place by k positions.
 // If k > 0, elements are rotated rightward.
 // If k < 0, elements are rotated leftward.
@@ -189,6 +337,40 @@ func Rotate[T any, Slice ~[]T](ss Slice, k int) {
 	}
 }
 
+// MoveRange moves the elements of the half-open span ss[start:end] in-place
+// so that they begin at offset dest of the result, preserving the relative
+// order of all the other elements. MoveRange will panic if start, end, or
+// dest is out of range, where dest ≤ len(ss)-(end-start).
+//
+// For example, if
+//
+//	ss := []string{"a", "b", "c", "d", "e"}
+//
+// then slice.MoveRange(ss, 1, 3, 2) produces
+//
+//	{"a", "d", "b", "c", "e"}
+//
+// while slice.MoveRange(ss, 3, 5, 0) produces
+//
+//	{"d", "e", "a", "b", "c"}
+//
+// Like [Rotate], MoveRange takes time proportional to the distance moved plus
+// the length of the span, but does not allocate storage outside the input.
+func MoveRange[T any, Slice ~[]T](ss Slice, start, end, dest int) {
+	if start < 0 || end < start || end > len(ss) {
+		panic("index out of range")
+	}
+	n := end - start
+	if dest < 0 || dest > len(ss)-n {
+		panic("index out of range")
+	}
+	if dest < start {
+		Rotate(ss[dest:end], n) // the span moves leftward
+	} else if dest > start {
+		Rotate(ss[start:dest+n], -n) // the span moves rightward
+	}
+}
+
 func gcd(a, b int) int {
 	for b != 0 {
 		a, b = b, a%b
@@ -260,6 +442,74 @@ func Stripe[T any, Slice ~[]T](vs []Slice, i int) Slice {
 	return out
 }
 
+// Stripes returns an iterator over the stripes of vs, as defined by [Stripe],
+// for each offset i in order, stopping when none of the slices has an ith
+// element. Each stripe is newly allocated.
+func Stripes[T any, Slice ~[]T](vs []Slice) iter.Seq2[int, Slice] {
+	return func(yield func(int, Slice) bool) {
+		for i := 0; ; i++ {
+			s := Stripe(vs, i)
+			if len(s) == 0 || !yield(i, s) {
+				return
+			}
+		}
+	}
+}
+
+// Transpose returns the transposition of the rectangular matrix vss, in which
+// the ith slice of the result holds the ith elements of each slice in vss,
+// in order.  It is equivalent to calling [Stripe] for each column, but the
+// results share a single allocation. Transpose returns nil if vss is empty or
+// its slices are empty.
+//
+// Transpose will panic if the slices of vss do not all have the same length.
+// Use [TransposeRagged] to transpose slices of unequal length.
+func Transpose[T any, Slice ~[]T](vss []Slice) []Slice {
+	for _, vs := range vss {
+		if len(vs) != len(vss[0]) {
+			panic("ragged input")
+		}
+	}
+	return TransposeRagged(vss)
+}
+
+// TransposeRagged returns the transposition of vss, in which the ith slice of
+// the result holds the ith elements of each slice in vss that has one, in
+// order, as defined by [Stripe]. The result has one slice for each element of
+// the longest slice of vss. TransposeRagged returns nil if vss is empty or its
+// slices are empty.
+func TransposeRagged[T any, Slice ~[]T](vss []Slice) []Slice {
+	var ncol, total int
+	for _, vs := range vss {
+		ncol = max(ncol, len(vs))
+		total += len(vs)
+	}
+	if ncol == 0 {
+		return nil
+	}
+
+	// Count the number of elements in each column, and carve the columns out
+	// of a single buffer.
+	out := make([]Slice, ncol)
+	buf := make(Slice, 0, total)
+	for i := range out {
+		var n int
+		for _, vs := range vss {
+			if i < len(vs) {
+				n++
+			}
+		}
+		out[i] = buf[len(buf) : len(buf) : len(buf)+n]
+		buf = buf[:len(buf)+n]
+	}
+	for _, vs := range vss {
+		for i, v := range vs {
+			out[i] = append(out[i], v)
+		}
+	}
+	return out
+}
+
 // Head returns a subslice of up to n elements from the head (front) of vs.  If
 // vs has fewer than n elements, the whole slice is returned.
 func Head[T any, Slice ~[]T](vs Slice, n int) Slice {
@@ -278,6 +528,85 @@ func Tail[T any, Slice ~[]T](vs Slice, n int) Slice {
 	return vs[len(vs)-n:]
 }
 
+// PadTo returns a slice of exactly n elements, consisting of the first n
+// elements of vs followed by as many copies of fill as are needed to make up
+// the length. If n ≤ len(vs), PadTo returns vs[:n]. Otherwise, the result
+// reuses the storage of vs if it has sufficient capacity, and is newly
+// allocated if not.  PadTo will panic if n < 0.
+//
+// PadTo is useful to normalize the lengths of slices before calling [Stripe]
+// or [Transpose].
+func PadTo[T any, Slice ~[]T](vs Slice, n int, fill T) Slice {
+	if n < 0 {
+		panic("n out of range")
+	} else if n <= len(vs) {
+		return vs[:n]
+	}
+	m := len(vs)
+	vs = slices.Grow(vs, n-m)[:n]
+	for i := m; i < n; i++ {
+		vs[i] = fill
+	}
+	return vs
+}
+
+// Resize returns a slice of exactly n elements, consisting of the first n
+// elements of vs followed by zero values. It is equivalent to calling
+// [PadTo] with a zero fill value.
+func Resize[T any, Slice ~[]T](vs Slice, n int) Slice {
+	var zero T
+	return PadTo(vs, n, zero)
+}
+
+// CommonPrefix returns the longest prefix shared by all the slices in vss.
+// The result is a subslice of vss[0], with its capacity clipped to its
+// length. If vss is empty, CommonPrefix returns nil.
+//
+// For example:
+//
+//	CommonPrefix([]int{1, 2, 3, 4}, []int{1, 2, 5})  // [1, 2]
+func CommonPrefix[T comparable, Slice ~[]T](vss ...Slice) Slice {
+	if len(vss) == 0 {
+		return nil
+	}
+	n := len(vss[0])
+	for _, vs := range vss[1:] {
+		n = min(n, len(vs))
+		for i := range n {
+			if vs[i] != vss[0][i] {
+				n = i
+				break
+			}
+		}
+	}
+	return vss[0][:n:n]
+}
+
+// CommonSuffix returns the longest suffix shared by all the slices in vss.
+// The result is a subslice of vss[0]. If vss is empty, CommonSuffix returns
+// nil.
+//
+// For example:
+//
+//	CommonSuffix([]int{1, 2, 3, 4}, []int{5, 3, 4})  // [3, 4]
+func CommonSuffix[T comparable, Slice ~[]T](vss ...Slice) Slice {
+	if len(vss) == 0 {
+		return nil
+	}
+	base := vss[0]
+	n := len(base)
+	for _, vs := range vss[1:] {
+		n = min(n, len(vs))
+		for i := 1; i <= n; i++ {
+			if vs[len(vs)-i] != base[len(base)-i] {
+				n = i - 1
+				break
+			}
+		}
+	}
+	return base[len(base)-n:]
+}
+
 // Select returns an iterator over the elements v of vs for which f(v) is true,
 // in the same order they occur in the input.
 func Select[T any, Slice ~[]T](vs Slice, f func(T) bool) iter.Seq[T] {
@@ -289,3 +618,396 @@ func Select[T any, Slice ~[]T](vs Slice, f func(T) bool) iter.Seq[T] {
 		}
 	}
 }
+
+// Reject returns an iterator over the elements v of vs for which f(v) is
+// false, in the same order they occur in the input. It is the complement of
+// [Select].
+func Reject[T any, Slice ~[]T](vs Slice, f func(T) bool) iter.Seq[T] {
+	return Select(vs, func(v T) bool { return !f(v) })
+}
+
+// SelectIndex returns an iterator over the offsets and values of the elements
+// v of vs for which f(v) is true, in the same order they occur in the input.
+func SelectIndex[T any, Slice ~[]T](vs Slice, f func(T) bool) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, v := range vs {
+			if f(v) && !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+// Zip returns an iterator over corresponding pairs of elements from as and bs.
+// The iterator stops when either input is exhausted, so the number of pairs
+// delivered is min(len(as), len(bs)).
+func Zip[A, B any, AS ~[]A, BS ~[]B](as AS, bs BS) iter.Seq2[A, B] {
+	return func(yield func(A, B) bool) {
+		n := min(len(as), len(bs))
+		for i := range n {
+			if !yield(as[i], bs[i]) {
+				return
+			}
+		}
+	}
+}
+
+// A Pair is a combination of two values of possibly-different types.
+type Pair[A, B any] struct {
+	X A
+	Y B
+}
+
+// Unzip splits a slice of pairs into separate slices of the X and Y values.
+// The results have the same length as ps. Unzip returns nil, nil if ps is
+// empty.
+func Unzip[A, B any](ps []Pair[A, B]) ([]A, []B) {
+	if len(ps) == 0 {
+		return nil, nil
+	}
+	as, bs := make([]A, len(ps)), make([]B, len(ps))
+	for i, p := range ps {
+		as[i], bs[i] = p.X, p.Y
+	}
+	return as, bs
+}
+
+// MapErr returns a slice of the results of applying f to each element of vs
+// in order. If f reports an error, MapErr stops and returns the results for
+// the elements preceding the failure, along with an *IndexError that records
+// the offset of the failing element and wraps the error from f. If vs is
+// empty, MapErr returns nil, nil.
+func MapErr[T, U any, Slice ~[]T](vs Slice, f func(T) (U, error)) ([]U, error) {
+	if len(vs) == 0 {
+		return nil, nil
+	}
+	out := make([]U, 0, len(vs))
+	for i, v := range vs {
+		u, err := f(v)
+		if err != nil {
+			return out, &IndexError{Index: i, Err: err}
+		}
+		out = append(out, u)
+	}
+	return out, nil
+}
+
+// An IndexError reports an error for the element at a particular offset of a
+// slice.
+type IndexError struct {
+	Index int   // the offset of the element
+	Err   error // the error reported for the element
+}
+
+// Error implements the error interface.
+func (e *IndexError) Error() string { return fmt.Sprintf("index %d: %v", e.Index, e.Err) }
+
+// Unwrap returns the underlying error of e.
+func (e *IndexError) Unwrap() error { return e.Err }
+
+// Reduce combines the elements of vs into a single value, by calling f on an
+// accumulator and each element of vs in order. The accumulator starts at init
+// and is replaced by each result of f. If vs is empty, Reduce returns init.
+//
+// For example, to compute the total length of a slice of strings:
+//
+//	n := slice.Reduce(ss, 0, func(n int, s string) int { return n + len(s) })
+func Reduce[T, U any, Slice ~[]T](vs Slice, init U, f func(U, T) U) U {
+	acc := init
+	for _, v := range vs {
+		acc = f(acc, v)
+	}
+	return acc
+}
+
+// Fold combines the elements of vs into a single value, by calling f on an
+// accumulator and each element of vs in order. The accumulator starts at the
+// first element of vs. If vs is empty, Fold returns a zero value; if vs has
+// one element, Fold returns that element without calling f.
+func Fold[T any, Slice ~[]T](vs Slice, f func(T, T) T) T {
+	if len(vs) == 0 {
+		var zero T
+		return zero
+	}
+	return Reduce(vs[1:], vs[0], f)
+}
+
+// Scan returns an iterator over the running accumulation of f over the
+// elements of vs, as computed by Reduce. The iterator yields one value for
+// each element of vs, and the last value yielded (if any) is equal to the
+// result of Reduce(vs, init, f).
+func Scan[T, U any, Slice ~[]T](vs Slice, init U, f func(U, T) U) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		acc := init
+		for _, v := range vs {
+			acc = f(acc, v)
+			if !yield(acc) {
+				return
+			}
+		}
+	}
+}
+
+// Shuffle permutes the elements of vs in-place into a uniformly random order
+// chosen using rng.
+func Shuffle[T any, Slice ~[]T](vs Slice, rng *rand.Rand) {
+	rng.Shuffle(len(vs), func(i, j int) { vs[i], vs[j] = vs[j], vs[i] })
+}
+
+// Sample returns a uniform random sample of up to k elements from seq, chosen
+// using rng. If seq yields fewer than k elements, all of them are returned.
+// The order of the elements in the result is unspecified. Sample consumes all
+// of seq, but stores at most k elements at a time.
+//
+// Sample will panic if k < 0. If k == 0, Sample returns nil without reading
+// from seq.
+func Sample[T any](seq iter.Seq[T], k int, rng *rand.Rand) []T {
+	if k < 0 {
+		panic("k out of range")
+	} else if k == 0 {
+		return nil
+	}
+
+	// Reservoir sampling: Fill the reservoir with the first k elements, then
+	// replace a random element with the nth with probability k/n.
+	var out []T
+	n := 0
+	for v := range seq {
+		n++
+		if len(out) < k {
+			out = append(out, v)
+		} else if j := rng.IntN(n); j < k {
+			out[j] = v
+		}
+	}
+	return out
+}
+
+// Flatten returns a new slice containing the concatenation of the elements of
+// vss, in order. The result is allocated once, with exactly the required
+// length. If the total length is zero, Flatten returns nil.
+func Flatten[T any, Slice ~[]T](vss []Slice) Slice {
+	var n int
+	for _, vs := range vss {
+		n += len(vs)
+	}
+	if n == 0 {
+		return nil
+	}
+	out := make(Slice, 0, n)
+	for _, vs := range vss {
+		out = append(out, vs...)
+	}
+	return out
+}
+
+// FlattenSeq returns a new slice containing the concatenation of the slices
+// yielded by seq, in order. If the total length is zero, FlattenSeq returns
+// nil.
+//
+// Because seq may only be traversed once, FlattenSeq retains the slices it
+// yields in order to allocate the result once, with exactly the required
+// length. The slices are not copied until the result is allocated.
+func FlattenSeq[T any, Slice ~[]T](seq iter.Seq[Slice]) Slice {
+	var vss []Slice
+	for vs := range seq {
+		vss = append(vss, vs)
+	}
+	return Flatten(vss)
+}
+
+// SplitOn returns an iterator over the subslices of vs separated by elements
+// equal to sep. It is shorthand for SplitFunc with an equality test.
+func SplitOn[T comparable, Slice ~[]T](vs Slice, sep T) iter.Seq[Slice] {
+	return SplitFunc(vs, func(v T) bool { return v == sep })
+}
+
+// SplitFunc returns an iterator over the subslices of vs separated by elements
+// v for which isSep(v) is true, analogous to [strings.Split]. The separators
+// are not included in the subslices. If vs contains k separators, the
+// iterator yields k+1 subslices, some of which may be empty; in particular if
+// vs is empty, the iterator yields a single empty subslice.
+//
+// The subslices share storage with vs, but their capacities are clipped to
+// their lengths, so that appending to one will not modify the elements of vs
+// after it.
+func SplitFunc[T any, Slice ~[]T](vs Slice, isSep func(T) bool) iter.Seq[Slice] {
+	return func(yield func(Slice) bool) {
+		i := 0
+		for j, v := range vs {
+			if isSep(v) {
+				if !yield(vs[i:j:j]) {
+					return
+				}
+				i = j + 1
+			}
+		}
+		yield(vs[i:len(vs):len(vs)])
+	}
+}
+
+// ChunkBy returns an iterator over the maximal runs of adjacent elements of
+// vs that have the same key, in order. The key of an element v is key(v). The
+// runs are subslices of vs with their capacities clipped to their lengths, and
+// together they cover the input. If vs is empty, ChunkBy yields nothing.
+//
+// For example, given
+//
+//	key := func(v int) bool { return v%2 == 0 }
+//
+// ChunkBy([]int{2, 4, 1, 3, 5, 6}, key) yields [2 4], [1 3 5], and [6].
+func ChunkBy[T any, K comparable, Slice ~[]T](vs Slice, key func(T) K) iter.Seq[Slice] {
+	return func(yield func(Slice) bool) {
+		if len(vs) == 0 {
+			return
+		}
+		i, cur := 0, key(vs[0])
+		for j := 1; j < len(vs); j++ {
+			if k := key(vs[j]); k != cur {
+				if !yield(vs[i:j:j]) {
+					return
+				}
+				i, cur = j, k
+			}
+		}
+		yield(vs[i:len(vs):len(vs)])
+	}
+}
+
+// ChunkFunc returns an iterator over the maximal runs of adjacent elements of
+// vs for which same reports true on each adjacent pair, in order. A new run
+// begins at each offset j > 0 for which same(vs[j-1], vs[j]) is false.  As
+// with [ChunkBy], the runs are subslices of vs with their capacities clipped
+// to their lengths, and together they cover the input.
+//
+// For example, given
+//
+//	same := func(a, b int) bool { return b == a+1 }
+//
+// ChunkFunc([]int{1, 2, 3, 5, 6, 8}, same) yields [1 2 3], [5 6], and [8].
+func ChunkFunc[T any, Slice ~[]T](vs Slice, same func(a, b T) bool) iter.Seq[Slice] {
+	return func(yield func(Slice) bool) {
+		if len(vs) == 0 {
+			return
+		}
+		i := 0
+		for j := 1; j < len(vs); j++ {
+			if !same(vs[j-1], vs[j]) {
+				if !yield(vs[i:j:j]) {
+					return
+				}
+				i = j
+			}
+		}
+		yield(vs[i:len(vs):len(vs)])
+	}
+}
+
+// Interleave returns a new slice containing the elements of vss in
+// round-robin order: The first element of each slice in turn, then the second
+// element of each, and so on. Slices that are exhausted are skipped.  This is
+// the inverse of extracting each [Stripe] of the result.  If the total length
+// is zero, Interleave returns nil.
+//
+// For example:
+//
+//	Interleave([]int{1, 2, 3}, []int{4}, []int{5, 6})
+//
+// produces
+//
+//	[1, 4, 5, 2, 6, 3]
+func Interleave[T any, Slice ~[]T](vss ...Slice) Slice {
+	var n, maxLen int
+	for _, vs := range vss {
+		n += len(vs)
+		maxLen = max(maxLen, len(vs))
+	}
+	if n == 0 {
+		return nil
+	}
+	out := make(Slice, 0, n)
+	for i := range maxLen {
+		for _, vs := range vss {
+			if i < len(vs) {
+				out = append(out, vs[i])
+			}
+		}
+	}
+	return out
+}
+
+// InterleaveSeq returns an iterator over the values of seqs in round-robin
+// order, as defined by [Interleave]. Each input is consumed only as needed to
+// deliver the next value, so the inputs may be unbounded.
+func InterleaveSeq[T any](seqs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		nexts := make([]func() (T, bool), len(seqs))
+		for i, seq := range seqs {
+			next, stop := iter.Pull(seq)
+			defer stop()
+			nexts[i] = next
+		}
+		for len(nexts) != 0 {
+			live := nexts[:0]
+			for _, next := range nexts {
+				v, ok := next()
+				if !ok {
+					continue
+				} else if !yield(v) {
+					return
+				}
+				live = append(live, next)
+			}
+			nexts = live
+		}
+	}
+}
+
+// A Run is a maximal sequence of Count adjacent copies of Value.
+type Run[T any] struct {
+	Value T
+	Count int
+}
+
+// RunLength returns the run-length encoding of vs, as a slice of runs of equal
+// adjacent elements in order. Each run has a Count of at least 1, and no two
+// adjacent runs have equal values. RunLength returns nil if vs is empty.
+//
+// For example:
+//
+//	RunLength([]string{"a", "a", "b", "a"})
+//
+// returns:
+//
+//	[{a 2} {b 1} {a 1}]
+func RunLength[T comparable, Slice ~[]T](vs Slice) []Run[T] {
+	var out []Run[T]
+	for _, v := range vs {
+		if n := len(out); n != 0 && out[n-1].Value == v {
+			out[n-1].Count++
+		} else {
+			out = append(out, Run[T]{Value: v, Count: 1})
+		}
+	}
+	return out
+}
+
+// RunLengthDecode returns the slice encoded by runs, the inverse of
+// [RunLength]. Runs with a Count of zero or less contribute no elements.
+// RunLengthDecode returns nil if the total length is 0.
+func RunLengthDecode[T any](runs []Run[T]) []T {
+	var n int
+	for _, r := range runs {
+		n += max(r.Count, 0)
+	}
+	if n == 0 {
+		return nil
+	}
+	out := make([]T, 0, n)
+	for _, r := range runs {
+		for range r.Count {
+			out = append(out, r.Value)
+		}
+	}
+	return out
+}
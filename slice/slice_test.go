@@ -1,8 +1,12 @@
 package slice_test
 
 import (
+	"errors"
+	"iter"
+	"math/rand/v2"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -103,6 +107,76 @@ func TestMapKeys(t *testing.T) {
 	}
 }
 
+func TestFrequency(t *testing.T) {
+	tests := []struct {
+		input []string
+		want  map[string]int
+	}{
+		{nil, map[string]int{}},
+		{[]string{"a"}, map[string]int{"a": 1}},
+		{[]string{"a", "b", "a", "c", "a", "b"}, map[string]int{"a": 3, "b": 2, "c": 1}},
+	}
+	for _, tc := range tests {
+		got := slice.Frequency(tc.input)
+		if diff := cmp.Diff(got, tc.want); diff != "" {
+			t.Errorf("Frequency(%q) (-got, +want):\n%s", tc.input, diff)
+		}
+	}
+
+	t.Run("Func", func(t *testing.T) {
+		got := slice.FrequencyFunc([]string{"apple", "fig", "kiwi", "pear", "plum", "grape"},
+			func(s string) int { return len(s) })
+		if diff := cmp.Diff(got, map[int]int{3: 1, 4: 3, 5: 2}); diff != "" {
+			t.Errorf("FrequencyFunc (-got, +want):\n%s", diff)
+		}
+		if got := slice.FrequencyFunc([]int(nil), func(int) bool { return true }); got == nil || len(got) != 0 {
+			t.Errorf("FrequencyFunc(nil): got %v, want empty", got)
+		}
+	})
+}
+
+func TestEqualUnordered(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"", "", true},
+		{"a", "", false},
+		{"", "a", false},
+		{"a", "a", true},
+		{"a b", "b a", true},
+		{"a b c", "c a b", true},
+		{"a a b", "a b b", false},
+		{"a a b", "b a a", true},
+		{"a b", "a b c", false},
+		{"a b c", "a b d", false},
+	}
+	for _, tc := range tests {
+		a, b := strings.Fields(tc.a), strings.Fields(tc.b)
+		if got := slice.EqualUnordered(a, b); got != tc.want {
+			t.Errorf("EqualUnordered(%q, %q): got %v, want %v", a, b, got, tc.want)
+		}
+		if got := slice.EqualUnorderedFunc(a, b, strings.Compare); got != tc.want {
+			t.Errorf("EqualUnorderedFunc(%q, %q): got %v, want %v", a, b, got, tc.want)
+		}
+	}
+
+	t.Run("Func", func(t *testing.T) {
+		// Elements equivalent under the comparison function count as equal.
+		a := []string{"Apple", "pear", "PEAR"}
+		b := []string{"pEaR", "APPLE", "Pear"}
+		before := slices.Clone(a)
+		if !slice.EqualUnorderedFunc(a, b, func(x, y string) int {
+			return strings.Compare(strings.ToLower(x), strings.ToLower(y))
+		}) {
+			t.Errorf("EqualUnorderedFunc(%q, %q): got false, want true", a, b)
+		}
+		if diff := cmp.Diff(a, before); diff != "" {
+			t.Errorf("Input modified (-got, +want):\n%s", diff)
+		}
+	})
+}
+
 func TestMatchingKeys(t *testing.T) {
 	even := func(z int) bool { return z%2 == 0 }
 	big := func(z int) bool { return z > 10 }
@@ -193,6 +267,47 @@ func TestRotate(t *testing.T) {
 	})
 }
 
+func TestMoveRange(t *testing.T) {
+	tests := []struct {
+		input            string
+		start, end, dest int
+		want             string
+	}{
+		{"", 0, 0, 0, ""},
+		{"a", 0, 1, 0, "a"},
+		{"a", 1, 1, 0, "a"},
+		{"a b c d e", 2, 2, 4, "a b c d e"},
+		{"a b c d e", 0, 5, 0, "a b c d e"},
+
+		{"a b c d e", 1, 3, 1, "a b c d e"},
+		{"a b c d e", 1, 3, 0, "b c a d e"},
+		{"a b c d e", 1, 3, 2, "a d b c e"},
+		{"a b c d e", 1, 3, 3, "a d e b c"},
+		{"a b c d e", 3, 5, 0, "d e a b c"},
+		{"a b c d e", 0, 1, 4, "b c d e a"},
+		{"a b c d e", 4, 5, 0, "e a b c d"},
+		{"a b c d e f g h", 2, 5, 4, "a b f g c d e h"},
+		{"a b c d e f g h", 5, 8, 1, "a f g h b c d e"},
+	}
+	for _, tc := range tests {
+		got := strings.Fields(tc.input)
+		slice.MoveRange(got, tc.start, tc.end, tc.dest)
+		if diff := cmp.Diff(got, strings.Fields(tc.want)); diff != "" {
+			t.Errorf("MoveRange %q %d %d %d (-got, +want):\n%s",
+				tc.input, tc.start, tc.end, tc.dest, diff)
+		}
+	}
+
+	t.Run("Bounds", func(t *testing.T) {
+		in := []string{"a", "b", "c", "d"}
+		mtest.MustPanic(t, func() { slice.MoveRange(in, -1, 2, 0) })
+		mtest.MustPanic(t, func() { slice.MoveRange(in, 2, 1, 0) })
+		mtest.MustPanic(t, func() { slice.MoveRange(in, 0, 5, 0) })
+		mtest.MustPanic(t, func() { slice.MoveRange(in, 0, 2, -1) })
+		mtest.MustPanic(t, func() { slice.MoveRange(in, 0, 2, 3) })
+	})
+}
+
 func TestAt(t *testing.T) {
 	mtest.MustPanic(t, func() { slice.At([]int(nil), 0) })
 	mtest.MustPanic(t, func() { slice.At([]int{1, 2}, 5) })
@@ -228,6 +343,42 @@ func TestAt(t *testing.T) {
 	}
 }
 
+func TestSwapRemove(t *testing.T) {
+	mtest.MustPanic(t, func() { slice.SwapRemove([]int(nil), 0) })
+	mtest.MustPanic(t, func() { slice.SwapRemove([]int{1, 2}, 2) })
+	mtest.MustPanic(t, func() { slice.SwapRemove([]int{1, 2}, -3) })
+
+	tests := []struct {
+		input string
+		k     int
+		want  string
+		out   string
+	}{
+		{"X", 0, "", "X"},
+		{"X", -1, "", "X"},
+		{"A B", 0, "B", "A"},
+		{"A B", 1, "A", "B"},
+		{"A B", -2, "B", "A"},
+		{"A B C D E", 0, "E B C D", "A"},
+		{"A B C D E", 2, "A B E D", "C"},
+		{"A B C D E", 4, "A B C D", "E"},
+		{"A B C D E", -2, "A B C E", "D"},
+	}
+	for _, tc := range tests {
+		input := strings.Fields(tc.input)
+		got, out := slice.SwapRemove(input, tc.k)
+		if diff := cmp.Diff(got, strings.Fields(tc.want), cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("SwapRemove %q %d (-got, +want):\n%s", tc.input, tc.k, diff)
+		}
+		if out != tc.out {
+			t.Errorf("SwapRemove %q %d: removed %q, want %q", tc.input, tc.k, out, tc.out)
+		}
+		if v := input[len(input)-1]; v != "" {
+			t.Errorf("SwapRemove %q %d: vacated element is %q, want zero", tc.input, tc.k, v)
+		}
+	}
+}
+
 func TestPtrAt(t *testing.T) {
 	tests := []struct {
 		input string
@@ -271,6 +422,40 @@ func TestPtrAt(t *testing.T) {
 	}
 }
 
+func TestRange(t *testing.T) {
+	tests := []struct {
+		input string
+		i, j  int
+		want  string
+	}{
+		{"", 0, 0, ""},
+		{"", -1, 5, ""},
+
+		{"a b c d e", 0, 5, "a b c d e"},
+		{"a b c d e", 1, 3, "b c"},
+		{"a b c d e", 2, 2, ""},
+		{"a b c d e", 3, 1, ""},
+		{"a b c d e", 0, -1, "a b c d"},
+		{"a b c d e", 1, -1, "b c d"},
+		{"a b c d e", -2, 5, "d e"},
+		{"a b c d e", -3, -1, "c d"},
+		{"a b c d e", -1, -3, ""},
+
+		// Out-of-range offsets are clipped.
+		{"a b c d e", -10, 2, "a b"},
+		{"a b c d e", 3, 10, "d e"},
+		{"a b c d e", -10, 10, "a b c d e"},
+		{"a b c d e", 6, 10, ""},
+		{"a b c d e", -10, -6, ""},
+	}
+	for _, tc := range tests {
+		got := slice.Range(strings.Fields(tc.input), tc.i, tc.j)
+		if diff := cmp.Diff(got, strings.Fields(tc.want)); diff != "" {
+			t.Errorf("Range %q %d %d (-got, +want):\n%s", tc.input, tc.i, tc.j, diff)
+		}
+	}
+}
+
 func TestChunks(t *testing.T) {
 	tests := []struct {
 		input string
@@ -402,6 +587,125 @@ func TestStripe(t *testing.T) {
 				tc.i, strings.ReplaceAll(tc.input, "|", "\n"), diff)
 		}
 	}
+
+	t.Run("Stripes", func(t *testing.T) {
+		stripes := make(map[string][]string) // :: input → non-empty stripes
+		for _, tc := range tests {
+			if tc.want != "" {
+				stripes[tc.input] = append(stripes[tc.input], tc.want)
+			}
+		}
+		for input, want := range stripes {
+			var got []string
+			for i, s := range slice.Stripes(makeInput(input)) {
+				if i != len(got) {
+					t.Errorf("Stripes %q: got index %d, want %d", input, i, len(got))
+				}
+				for j, w := range s {
+					if w == "" {
+						s[j] = "@"
+					}
+				}
+				got = append(got, strings.Join(s, " "))
+			}
+			if diff := cmp.Diff(got, want); diff != "" {
+				t.Errorf("Stripes %q (-got, +want):\n%s", input, diff)
+			}
+		}
+	})
+}
+
+func TestTranspose(t *testing.T) {
+	makeInput := func(s string) [][]string {
+		if s == "" {
+			return nil
+		}
+		var out [][]string
+		for _, row := range strings.Split(s, "|") {
+			out = append(out, strings.Fields(row))
+		}
+		return out
+	}
+	tests := []struct {
+		input, want string
+		ragged      bool
+	}{
+		{"", "", false},
+		{"|", "", false},
+		{"a", "a", false},
+		{"a b c", "a|b|c", false},
+		{"a|b|c", "a b c", false},
+		{"a b c|d e f", "a d|b e|c f", false},
+		{"a b|c d|e f", "a c e|b d f", false},
+
+		{"a b c|d e|f g h i", "a d f|b e g|c h|i", true},
+		{"|a b|c", "a c|b", true},
+	}
+	for _, tc := range tests {
+		input, want := makeInput(tc.input), makeInput(tc.want)
+		got := slice.TransposeRagged(input)
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Errorf("TransposeRagged %q (-got, +want):\n%s", tc.input, diff)
+		}
+		for i := range got {
+			if diff := cmp.Diff(got[i], slice.Stripe(input, i)); diff != "" {
+				t.Errorf("Column %d (-got, +stripe):\n%s", i, diff)
+			}
+		}
+		if tc.ragged {
+			mtest.MustPanic(t, func() { slice.Transpose(input) })
+		} else if diff := cmp.Diff(slice.Transpose(input), want); diff != "" {
+			t.Errorf("Transpose %q (-got, +want):\n%s", tc.input, diff)
+		}
+	}
+
+	// The columns of the result do not overlap.
+	got := slice.Transpose([][]int{{1, 2}, {3, 4}})
+	got[0] = append(got[0], 5)
+	if diff := cmp.Diff(got, [][]int{{1, 3, 5}, {2, 4}}); diff != "" {
+		t.Errorf("Append (-got, +want):\n%s", diff)
+	}
+}
+
+func TestCommonPrefix(t *testing.T) {
+	split := func(s string) []string {
+		if s == "-" {
+			return []string{}
+		}
+		return strings.Fields(s)
+	}
+	tests := []struct {
+		input          []string // each is a space-separated slice, "-" is empty
+		prefix, suffix string
+	}{
+		{nil, "", ""},
+		{[]string{"a b c"}, "a b c", "a b c"},
+		{[]string{"a b c", "a b c"}, "a b c", "a b c"},
+		{[]string{"a b c", "-"}, "", ""},
+		{[]string{"a b c", "a b"}, "a b", ""},
+		{[]string{"a b c", "b c"}, "", "b c"},
+		{[]string{"a b c d", "a b x c d"}, "a b", "c d"},
+		{[]string{"a b c d", "a b x c d", "a q c d"}, "a", "c d"},
+		{[]string{"a b c d", "a b x c d", "q d"}, "", "d"},
+		{[]string{"p a p", "p p", "p a b p"}, "p", "p"},
+	}
+	for _, tc := range tests {
+		var input [][]string
+		for _, s := range tc.input {
+			input = append(input, split(s))
+		}
+		pfx := slice.CommonPrefix(input...)
+		if diff := cmp.Diff(pfx, split(tc.prefix), cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("CommonPrefix %q (-got, +want):\n%s", tc.input, diff)
+		}
+		if cap(pfx) != len(pfx) {
+			t.Errorf("CommonPrefix %q: cap %d != len %d", tc.input, cap(pfx), len(pfx))
+		}
+		sfx := slice.CommonSuffix(input...)
+		if diff := cmp.Diff(sfx, split(tc.suffix), cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("CommonSuffix %q (-got, +want):\n%s", tc.input, diff)
+		}
+	}
 }
 
 func TestHead(t *testing.T) {
@@ -452,6 +756,56 @@ func TestTail(t *testing.T) {
 	}
 }
 
+func TestPadTo(t *testing.T) {
+	tests := []struct {
+		input string
+		n     int
+		want  string
+	}{
+		{"", 0, ""},
+		{"", 2, "- -"},
+		{"a b c", 0, ""},
+		{"a b c", 2, "a b"},
+		{"a b c", 3, "a b c"},
+		{"a b c", 5, "a b c - -"},
+	}
+	for _, tc := range tests {
+		got := slice.PadTo(strings.Fields(tc.input), tc.n, "-")
+		if diff := cmp.Diff(got, strings.Fields(tc.want), cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("PadTo %q %d (-got, +want):\n%s", tc.input, tc.n, diff)
+		}
+	}
+
+	t.Run("Reuse", func(t *testing.T) {
+		buf := make([]int, 2, 10)
+		buf[0], buf[1] = 1, 2
+		got := slice.PadTo(buf[:1], 4, 9)
+		if diff := cmp.Diff(got, []int{1, 9, 9, 9}); diff != "" {
+			t.Errorf("PadTo (-got, +want):\n%s", diff)
+		}
+		if &got[0] != &buf[0] {
+			t.Error("PadTo did not reuse the input storage")
+		}
+	})
+
+	t.Run("Resize", func(t *testing.T) {
+		got := slice.Resize([]int{1, 2, 3}, 5)
+		if diff := cmp.Diff(got, []int{1, 2, 3, 0, 0}); diff != "" {
+			t.Errorf("Resize (-got, +want):\n%s", diff)
+		}
+		got[4] = 7
+		got = slice.Resize(got[:4], 5) // reused storage is zeroed
+		if diff := cmp.Diff(got, []int{1, 2, 3, 0, 0}); diff != "" {
+			t.Errorf("Resize (-got, +want):\n%s", diff)
+		}
+		if got := slice.Resize(got, 2); len(got) != 2 {
+			t.Errorf("Resize: got length %d, want 2", len(got))
+		}
+	})
+
+	mtest.MustPanic(t, func() { slice.PadTo([]int{1}, -1, 0) })
+}
+
 func TestSelect(t *testing.T) {
 	tests := []struct {
 		input, want []int
@@ -469,12 +823,38 @@ func TestSelect(t *testing.T) {
 	}
 
 	isEven := func(z int) bool { return z%2 == 0 }
+	isOdd := func(z int) bool { return z%2 != 0 }
 	for _, tc := range tests {
 		got := slices.Collect(slice.Select(tc.input, isEven))
 		if diff := cmp.Diff(got, tc.want); diff != "" {
 			t.Errorf("Select %v (-got, +want):\n%s", tc.input, diff)
 		}
+		got = slices.Collect(slice.Reject(tc.input, isOdd))
+		if diff := cmp.Diff(got, tc.want); diff != "" {
+			t.Errorf("Reject %v (-got, +want):\n%s", tc.input, diff)
+		}
+
+		got = nil
+		for i, v := range slice.SelectIndex(tc.input, isEven) {
+			if tc.input[i] != v {
+				t.Errorf("SelectIndex %v: got value %d at %d, want %d", tc.input, v, i, tc.input[i])
+			}
+			got = append(got, v)
+		}
+		if diff := cmp.Diff(got, tc.want); diff != "" {
+			t.Errorf("SelectIndex %v (-got, +want):\n%s", tc.input, diff)
+		}
 	}
+
+	t.Run("Index", func(t *testing.T) {
+		var got []int
+		for i := range slice.SelectIndex([]int{8, 6, 7, 5, 3, 0, 9}, isEven) {
+			got = append(got, i)
+		}
+		if diff := cmp.Diff(got, []int{0, 1, 5}); diff != "" {
+			t.Errorf("SelectIndex (-got, +want):\n%s", diff)
+		}
+	})
 }
 
 func (tc *testCase[T]) partition(t *testing.T) {
@@ -497,6 +877,27 @@ func (tc *testCase[T]) partition(t *testing.T) {
 	if diff := cmp.Diff(cp, cp2); diff != "" {
 		t.Errorf("After append to result (-got, +want):\n%s", diff)
 	}
+
+	// Verify that the stable partition agrees, and preserves the order of the
+	// unkept elements.
+	var wantRest []T
+	for _, v := range tc.input {
+		if !tc.keep(v) {
+			wantRest = append(wantRest, v)
+		}
+	}
+	cp = copyOf(tc.input)
+	kept, rest := slice.PartitionStable(cp, tc.keep)
+	if diff := cmp.Diff(tc.want, kept, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("PartitionStable kept (-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(wantRest, rest, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("PartitionStable unkept (-want, +got)\n%s", diff)
+	}
+	if len(kept)+len(rest) != len(cp) || cap(kept) != len(kept) {
+		t.Errorf("PartitionStable: got len %d+%d cap %d, want len %d",
+			len(kept), len(rest), cap(kept), len(cp))
+	}
 }
 
 func copyOf[T any](vs []T) []T {
@@ -504,3 +905,416 @@ func copyOf[T any](vs []T) []T {
 	copy(out, vs)
 	return out
 }
+
+func TestZip(t *testing.T) {
+	type pair = slice.Pair[string, int]
+	tests := []struct {
+		as   []string
+		bs   []int
+		want []pair
+	}{
+		{nil, nil, nil},
+		{[]string{"a"}, nil, nil},
+		{nil, []int{1}, nil},
+		{[]string{"a"}, []int{1}, []pair{{"a", 1}}},
+		{[]string{"a", "b", "c"}, []int{1, 2}, []pair{{"a", 1}, {"b", 2}}},
+		{[]string{"a", "b"}, []int{1, 2, 3}, []pair{{"a", 1}, {"b", 2}}},
+		{[]string{"a", "b", "c"}, []int{1, 2, 3}, []pair{{"a", 1}, {"b", 2}, {"c", 3}}},
+	}
+	for _, tc := range tests {
+		var got []pair
+		for a, b := range slice.Zip(tc.as, tc.bs) {
+			got = append(got, pair{a, b})
+		}
+		if diff := cmp.Diff(got, tc.want); diff != "" {
+			t.Errorf("Zip(%q, %v) (-got, +want):\n%s", tc.as, tc.bs, diff)
+		}
+
+		as, bs := slice.Unzip(got)
+		n := min(len(tc.as), len(tc.bs))
+		if diff := cmp.Diff(as, tc.as[:n], cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("Unzip X (-got, +want):\n%s", diff)
+		}
+		if diff := cmp.Diff(bs, tc.bs[:n], cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("Unzip Y (-got, +want):\n%s", diff)
+		}
+	}
+
+	t.Run("Stop", func(t *testing.T) {
+		var got []int
+		for _, b := range slice.Zip([]string{"a", "b", "c"}, []int{1, 2, 3}) {
+			got = append(got, b)
+			if b == 2 {
+				break
+			}
+		}
+		if diff := cmp.Diff(got, []int{1, 2}); diff != "" {
+			t.Errorf("Zip break (-got, +want):\n%s", diff)
+		}
+	})
+}
+
+func TestMapErr(t *testing.T) {
+	got, err := slice.MapErr([]string{"1", "2", "3"}, strconv.Atoi)
+	if err != nil {
+		t.Fatalf("MapErr: unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(got, []int{1, 2, 3}); diff != "" {
+		t.Errorf("MapErr (-got, +want):\n%s", diff)
+	}
+
+	if got, err := slice.MapErr([]string(nil), strconv.Atoi); got != nil || err != nil {
+		t.Errorf("MapErr(nil): got (%v, %v), want (nil, nil)", got, err)
+	}
+
+	var calls int
+	got, err = slice.MapErr([]string{"4", "5", "bogus", "6"}, func(s string) (int, error) {
+		calls++
+		return strconv.Atoi(s)
+	})
+	var ie *slice.IndexError
+	if !errors.As(err, &ie) {
+		t.Fatalf("MapErr: got error %v, want *IndexError", err)
+	} else if ie.Index != 2 {
+		t.Errorf("MapErr: got index %d, want 2", ie.Index)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("MapErr: got error %v, want %v", err, strconv.ErrSyntax)
+	}
+	if calls != 3 {
+		t.Errorf("MapErr: got %d calls, want 3", calls)
+	}
+	if diff := cmp.Diff(got, []int{4, 5}); diff != "" {
+		t.Errorf("MapErr (-got, +want):\n%s", diff)
+	}
+}
+
+func TestReduce(t *testing.T) {
+	sum := func(a, b int) int { return a + b }
+	tests := []struct {
+		input []int
+		init  int
+		want  int
+		scan  []int
+	}{
+		{nil, 0, 0, nil},
+		{nil, 5, 5, nil},
+		{[]int{1}, 0, 1, []int{1}},
+		{[]int{1}, 10, 11, []int{11}},
+		{[]int{1, 2, 3, 4}, 0, 10, []int{1, 3, 6, 10}},
+		{[]int{1, 2, 3, 4}, 100, 110, []int{101, 103, 106, 110}},
+	}
+	for _, tc := range tests {
+		if got := slice.Reduce(tc.input, tc.init, sum); got != tc.want {
+			t.Errorf("Reduce(%v, %d): got %d, want %d", tc.input, tc.init, got, tc.want)
+		}
+		got := slices.Collect(slice.Scan(tc.input, tc.init, sum))
+		if diff := cmp.Diff(got, tc.scan); diff != "" {
+			t.Errorf("Scan(%v, %d) (-got, +want):\n%s", tc.input, tc.init, diff)
+		}
+	}
+
+	t.Run("Types", func(t *testing.T) {
+		got := slice.Reduce(strings.Fields("a bb ccc"), 0, func(n int, s string) int {
+			return n + len(s)
+		})
+		if got != 6 {
+			t.Errorf("Reduce: got %d, want 6", got)
+		}
+	})
+}
+
+func TestFold(t *testing.T) {
+	cat := func(a, b string) string { return a + "-" + b }
+	tests := []struct {
+		input, want string
+	}{
+		{"", ""},
+		{"a", "a"},
+		{"a b", "a-b"},
+		{"a b c d", "a-b-c-d"},
+	}
+	for _, tc := range tests {
+		if got := slice.Fold(strings.Fields(tc.input), cat); got != tc.want {
+			t.Errorf("Fold(%q): got %q, want %q", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestShuffle(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	slice.Shuffle([]int(nil), rng) // should not panic
+
+	input := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	got := slices.Clone(input)
+	slice.Shuffle(got, rng)
+	if slices.Equal(got, input) {
+		t.Errorf("Shuffle did not change the order: %v", got)
+	}
+	slices.Sort(got)
+	if diff := cmp.Diff(got, input); diff != "" {
+		t.Errorf("Shuffle contents (-got, +want):\n%s", diff)
+	}
+}
+
+func TestSample(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	input := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	sorted := func(vs []int) []int { slices.Sort(vs); return vs }
+
+	if got := slice.Sample(slices.Values(input), 0, rng); got != nil {
+		t.Errorf("Sample(*, 0): got %v, want nil", got)
+	}
+	if got := slice.Sample(slices.Values([]int(nil)), 3, rng); got != nil {
+		t.Errorf("Sample(empty, 3): got %v, want nil", got)
+	}
+	if got := sorted(slice.Sample(slices.Values(input), 20, rng)); !slices.Equal(got, input) {
+		t.Errorf("Sample(*, 20): got %v, want %v", got, input)
+	}
+
+	// Every element should be chosen at some point.
+	seen := make(map[int]int)
+	for range 200 {
+		got := slice.Sample(slices.Values(input), 3, rng)
+		if len(got) != 3 {
+			t.Fatalf("Sample(*, 3): got %v, want 3 elements", got)
+		}
+		if c := slices.Compact(sorted(slices.Clone(got))); len(c) != 3 {
+			t.Errorf("Sample(*, 3): got duplicates %v", got)
+		}
+		for _, v := range got {
+			seen[v]++
+		}
+	}
+	if len(seen) != len(input) {
+		t.Errorf("Sample: got samples %v, want all of %v", seen, input)
+	}
+
+	mtest.MustPanic(t, func() { slice.Sample(slices.Values(input), -1, rng) })
+}
+
+func TestFlatten(t *testing.T) {
+	tests := []struct {
+		input [][]int
+		want  []int
+	}{
+		{nil, nil},
+		{[][]int{}, nil},
+		{[][]int{nil, {}, nil}, nil},
+		{[][]int{{1}}, []int{1}},
+		{[][]int{{1, 2}, nil, {3}, {}, {4, 5, 6}}, []int{1, 2, 3, 4, 5, 6}},
+	}
+	for _, tc := range tests {
+		got := slice.Flatten(tc.input)
+		if diff := cmp.Diff(got, tc.want); diff != "" {
+			t.Errorf("Flatten(%v) (-got, +want):\n%s", tc.input, diff)
+		}
+		if len(got) != cap(got) {
+			t.Errorf("Flatten(%v): len %d != cap %d", tc.input, len(got), cap(got))
+		}
+
+		got = slice.FlattenSeq(slices.Values(tc.input))
+		if diff := cmp.Diff(got, tc.want); diff != "" {
+			t.Errorf("FlattenSeq(%v) (-got, +want):\n%s", tc.input, diff)
+		}
+	}
+}
+
+func TestSplitOn(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"", []string{""}},
+		{"a", []string{"a"}},
+		{"a b c", []string{"a b c"}},
+		{"|", []string{"", ""}},
+		{"a | b", []string{"a", "b"}},
+		{"| a b |", []string{"", "a b", ""}},
+		{"a | | b c | d", []string{"a", "", "b c", "d"}},
+	}
+	for _, tc := range tests {
+		var got []string
+		for seg := range slice.SplitOn(strings.Fields(tc.input), "|") {
+			if len(seg) != cap(seg) {
+				t.Errorf("Segment %q: len %d != cap %d", seg, len(seg), cap(seg))
+			}
+			got = append(got, strings.Join(seg, " "))
+		}
+		if diff := cmp.Diff(got, tc.want); diff != "" {
+			t.Errorf("SplitOn(%q) (-got, +want):\n%s", tc.input, diff)
+		}
+	}
+
+	t.Run("Func", func(t *testing.T) {
+		input := []int{1, 2, 0, 3, -1, 4, 5, 0}
+		var got [][]int
+		for seg := range slice.SplitFunc(input, func(z int) bool { return z <= 0 }) {
+			got = append(got, seg)
+			if len(got) == 3 {
+				break
+			}
+		}
+		if diff := cmp.Diff(got, [][]int{{1, 2}, {3}, {4, 5}}); diff != "" {
+			t.Errorf("SplitFunc (-got, +want):\n%s", diff)
+		}
+	})
+}
+
+func TestChunkBy(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"", nil},
+		{"a", []string{"a"}},
+		{"a b c", []string{"a b c"}},
+		{"a b C", []string{"a b", "C"}},
+		{"A b c D E f", []string{"A", "b c", "D E", "f"}},
+		{"A B C", []string{"A B C"}},
+	}
+	isUpper := func(s string) bool { return strings.ToUpper(s) == s }
+	for _, tc := range tests {
+		var got []string
+		for seg := range slice.ChunkBy(strings.Fields(tc.input), isUpper) {
+			if len(seg) != cap(seg) {
+				t.Errorf("Chunk %q: len %d != cap %d", seg, len(seg), cap(seg))
+			}
+			got = append(got, strings.Join(seg, " "))
+		}
+		if diff := cmp.Diff(got, tc.want); diff != "" {
+			t.Errorf("ChunkBy(%q) (-got, +want):\n%s", tc.input, diff)
+		}
+	}
+
+	t.Run("Stop", func(t *testing.T) {
+		input := []int{1, 1, 2, 3, 3, 3, 4}
+		var got [][]int
+		for seg := range slice.ChunkBy(input, func(z int) int { return z }) {
+			got = append(got, seg)
+			if len(got) == 3 {
+				break
+			}
+		}
+		if diff := cmp.Diff(got, [][]int{{1, 1}, {2}, {3, 3, 3}}); diff != "" {
+			t.Errorf("ChunkBy (-got, +want):\n%s", diff)
+		}
+	})
+
+	t.Run("Func", func(t *testing.T) {
+		tests := []struct {
+			input []int
+			want  [][]int
+		}{
+			{nil, nil},
+			{[]int{1}, [][]int{{1}}},
+			{[]int{1, 2, 3}, [][]int{{1, 2, 3}}},
+			{[]int{1, 2, 3, 5, 6, 8}, [][]int{{1, 2, 3}, {5, 6}, {8}}},
+			{[]int{3, 2, 1}, [][]int{{3}, {2}, {1}}},
+		}
+		succ := func(a, b int) bool { return b == a+1 }
+		for _, tc := range tests {
+			var got [][]int
+			for seg := range slice.ChunkFunc(tc.input, succ) {
+				if len(seg) != cap(seg) {
+					t.Errorf("Chunk %v: len %d != cap %d", seg, len(seg), cap(seg))
+				}
+				got = append(got, seg)
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("ChunkFunc(%v) (-got, +want):\n%s", tc.input, diff)
+			}
+		}
+	})
+}
+
+func TestInterleave(t *testing.T) {
+	tests := []struct {
+		input [][]int
+		want  []int
+	}{
+		{nil, nil},
+		{[][]int{nil, {}}, nil},
+		{[][]int{{1, 2, 3}}, []int{1, 2, 3}},
+		{[][]int{{1, 2}, {3, 4}}, []int{1, 3, 2, 4}},
+		{[][]int{{1, 2, 3}, {4}, {5, 6}}, []int{1, 4, 5, 2, 6, 3}},
+		{[][]int{{}, {1}, nil, {2, 3, 4}}, []int{1, 2, 3, 4}},
+	}
+	for _, tc := range tests {
+		got := slice.Interleave(tc.input...)
+		if diff := cmp.Diff(got, tc.want); diff != "" {
+			t.Errorf("Interleave(%v) (-got, +want):\n%s", tc.input, diff)
+		}
+
+		var seqs []iter.Seq[int]
+		for _, vs := range tc.input {
+			seqs = append(seqs, slices.Values(vs))
+		}
+		got = slices.Collect(slice.InterleaveSeq(seqs...))
+		if diff := cmp.Diff(got, tc.want); diff != "" {
+			t.Errorf("InterleaveSeq(%v) (-got, +want):\n%s", tc.input, diff)
+		}
+
+		// Interleave inverts Stripe when the inputs have equal length.
+		if len(tc.input) == 2 && len(tc.input[0]) == len(tc.input[1]) {
+			pairs := slice.Chunks(got, 2)
+			for i, in := range tc.input {
+				if diff := cmp.Diff(slice.Stripe(pairs, i), in, cmpopts.EquateEmpty()); diff != "" {
+					t.Errorf("Stripe %d (-got, +want):\n%s", i, diff)
+				}
+			}
+		}
+	}
+
+	t.Run("Unbounded", func(t *testing.T) {
+		count := func(start int) iter.Seq[int] {
+			return func(yield func(int) bool) {
+				for i := start; yield(i); i += 10 {
+				}
+			}
+		}
+		var got []int
+		for v := range slice.InterleaveSeq(count(0), count(5)) {
+			got = append(got, v)
+			if len(got) == 6 {
+				break
+			}
+		}
+		if diff := cmp.Diff(got, []int{0, 5, 10, 15, 20, 25}); diff != "" {
+			t.Errorf("InterleaveSeq (-got, +want):\n%s", diff)
+		}
+	})
+}
+
+func TestRunLength(t *testing.T) {
+	type run = slice.Run[string]
+	tests := []struct {
+		input []string
+		want  []run
+	}{
+		{nil, nil},
+		{[]string{}, nil},
+		{[]string{"a"}, []run{{"a", 1}}},
+		{[]string{"a", "a", "a"}, []run{{"a", 3}}},
+		{[]string{"a", "b", "c"}, []run{{"a", 1}, {"b", 1}, {"c", 1}}},
+		{[]string{"a", "a", "b", "a", "c", "c"}, []run{{"a", 2}, {"b", 1}, {"a", 1}, {"c", 2}}},
+	}
+	for _, tc := range tests {
+		got := slice.RunLength(tc.input)
+		if diff := cmp.Diff(got, tc.want); diff != "" {
+			t.Errorf("RunLength(%q) (-got, +want):\n%s", tc.input, diff)
+		}
+		dec := slice.RunLengthDecode(got)
+		if diff := cmp.Diff(dec, tc.input, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("RunLengthDecode(%v) (-got, +want):\n%s", got, diff)
+		}
+	}
+
+	t.Run("Decode", func(t *testing.T) {
+		got := slice.RunLengthDecode([]slice.Run[int]{{1, 2}, {2, 0}, {3, -1}, {4, 1}, {1, 2}})
+		if diff := cmp.Diff(got, []int{1, 1, 4, 1, 1}); diff != "" {
+			t.Errorf("RunLengthDecode (-got, +want):\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package slice
+
+import "maps"
+
+// LCString computes a longest common contiguous run of as and bs, and returns
+// its length n and its offsets i in as and j in bs, so that as[i:i+n] is equal
+// to bs[j:j+n].  If there is more than one longest run, LCString reports the
+// one that ends earliest in bs, at its earliest offset in as. If as and bs have
+// no elements in common, LCString returns 0, 0, 0.
+//
+// This implementation builds a suffix automaton for as, and takes O(m+n) time
+// and O(m) space for inputs of length m = len(as) and n = len(bs).
+func LCString[T comparable, Slice ~[]T](as, bs Slice) (i, j, n int) {
+	if len(as) == 0 || len(bs) == 0 {
+		return 0, 0, 0
+	}
+	sa := newSuffixAutomaton(as)
+
+	var best, bestState, bestEnd int
+	cur, size := 0, 0
+	for k, b := range bs {
+		for cur != 0 && !sa.has(cur, b) {
+			cur = sa.states[cur].link
+			size = sa.states[cur].size
+		}
+		if next, ok := sa.states[cur].next[b]; ok {
+			cur = next
+			size++
+		}
+		if size > best {
+			best, bestState, bestEnd = size, cur, k
+		}
+	}
+	if best == 0 {
+		return 0, 0, 0
+	}
+	return sa.states[bestState].first - best + 1, bestEnd - best + 1, best
+}
+
+// Index returns the offset of the first occurrence of needle in haystack, or
+// -1 if needle does not occur in haystack. If needle is empty, Index returns 0.
+//
+// This implementation uses the Knuth-Morris-Pratt algorithm, and takes
+// O(m+n) time and O(m) space for a needle of length m and a haystack of
+// length n.
+func Index[T comparable, Slice ~[]T](haystack, needle Slice) int {
+	return IndexFunc(haystack, needle, equal)
+}
+
+// IndexFunc is as [Index], but uses eq to compare elements. The function eq
+// must be an equivalence relation.
+func IndexFunc[T any, Slice ~[]T](haystack, needle Slice, eq func(a, b T) bool) int {
+	if len(needle) == 0 {
+		return 0
+	}
+	if pos := kmpSearch(haystack, needle, eq, 1); pos != nil {
+		return pos[0]
+	}
+	return -1
+}
+
+// IndexAll returns the offsets of the non-overlapping occurrences of needle in
+// haystack, in increasing order. Each occurrence begins at or after the end of
+// the previous one, so for example the needle "a a" occurs in "a a a a" at
+// offsets 0 and 2 only. If needle is empty or does not occur in haystack,
+// IndexAll returns nil.
+//
+// Like Index, this takes O(m+n) time and O(m) space.
+func IndexAll[T comparable, Slice ~[]T](haystack, needle Slice) []int {
+	return IndexAllFunc(haystack, needle, equal)
+}
+
+// IndexAllFunc is as [IndexAll], but uses eq to compare elements. The function
+// eq must be an equivalence relation.
+func IndexAllFunc[T any, Slice ~[]T](haystack, needle Slice, eq func(a, b T) bool) []int {
+	if len(needle) == 0 {
+		return nil
+	}
+	return kmpSearch(haystack, needle, eq, -1)
+}
+
+// kmpSearch returns the offsets of up to limit non-overlapping occurrences of
+// the non-empty needle in haystack, or all of them if limit < 0. It returns
+// nil if there are none.
+func kmpSearch[T any, Slice ~[]T](haystack, needle Slice, eq func(a, b T) bool, limit int) []int {
+	if len(needle) > len(haystack) {
+		return nil
+	}
+
+	// fail[i] is the length of the longest proper prefix of needle[:i+1] that
+	// is also a suffix of it.
+	fail := make([]int, len(needle))
+	for i, k := 1, 0; i < len(needle); i++ {
+		for k > 0 && !eq(needle[i], needle[k]) {
+			k = fail[k-1]
+		}
+		if eq(needle[i], needle[k]) {
+			k++
+		}
+		fail[i] = k
+	}
+
+	var pos []int
+	for i, k := 0, 0; i < len(haystack) && len(pos) != limit; i++ {
+		for k > 0 && !eq(haystack[i], needle[k]) {
+			k = fail[k-1]
+		}
+		if eq(haystack[i], needle[k]) {
+			k++
+		}
+		if k == len(needle) {
+			pos = append(pos, i-k+1)
+			k = 0 // do not overlap the match just found
+		}
+	}
+	return pos
+}
+
+// A suffixAutomaton is the minimal automaton recognizing the suffixes of a
+// sequence. Each state of the automaton represents a set of substrings that
+// share the same set of ending positions in the sequence.
+type suffixAutomaton[T comparable] struct {
+	states []saState[T]
+	last   int // the state for the whole sequence
+}
+
+type saState[T comparable] struct {
+	size  int       // the length of the longest substring in this state
+	link  int       // the suffix link, or -1 for the initial state
+	first int       // the end offset of the first occurrence of this state
+	next  map[T]int // transitions
+}
+
+func newSuffixAutomaton[T comparable, Slice ~[]T](vs Slice) *suffixAutomaton[T] {
+	sa := &suffixAutomaton[T]{states: make([]saState[T], 1, 2*len(vs))}
+	sa.states[0] = saState[T]{link: -1, next: make(map[T]int)}
+	for i, v := range vs {
+		sa.extend(i, v)
+	}
+	return sa
+}
+
+func (sa *suffixAutomaton[T]) has(s int, v T) bool { _, ok := sa.states[s].next[v]; return ok }
+
+// extend adds v at offset i to the end of the sequence recognized by sa.
+func (sa *suffixAutomaton[T]) extend(i int, v T) {
+	cur := len(sa.states)
+	sa.states = append(sa.states, saState[T]{
+		size:  sa.states[sa.last].size + 1,
+		first: i,
+		next:  make(map[T]int),
+	})
+
+	p := sa.last
+	for p >= 0 && !sa.has(p, v) {
+		sa.states[p].next[v] = cur
+		p = sa.states[p].link
+	}
+	if p < 0 {
+		sa.states[cur].link = 0
+	} else if q := sa.states[p].next[v]; sa.states[p].size+1 == sa.states[q].size {
+		sa.states[cur].link = q
+	} else {
+		// Split q by cloning it with the shorter length.
+		clone := len(sa.states)
+		sa.states = append(sa.states, saState[T]{
+			size:  sa.states[p].size + 1,
+			link:  sa.states[q].link,
+			first: sa.states[q].first,
+			next:  maps.Clone(sa.states[q].next),
+		})
+		for p >= 0 && sa.states[p].next[v] == q {
+			sa.states[p].next[v] = clone
+			p = sa.states[p].link
+		}
+		sa.states[q].link = clone
+		sa.states[cur].link = clone
+	}
+	sa.last = cur
+}
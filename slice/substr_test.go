@@ -0,0 +1,159 @@
+package slice_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/creachadair/mds/slice"
+)
+
+func TestLCString(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		i, j, n int
+	}{
+		{"", "", 0, 0, 0},
+		{"a b c", "", 0, 0, 0},
+		{"", "a b c", 0, 0, 0},
+		{"a b c", "d e f", 0, 0, 0},
+
+		{"a b c", "a b c", 0, 0, 3},
+		{"a b c d", "b c", 1, 0, 2},
+		{"b c", "a b c d", 0, 1, 2},
+		{"a b c d e", "e b c d a", 1, 1, 3},
+		{"x a b y a b c z", "a b c", 4, 0, 3},
+
+		// Ties are resolved in favor of the earliest end in b, and then the
+		// earliest offset in a.
+		{"a b x c d", "c d y a b", 3, 0, 2},
+		{"a b x a b", "q a b", 0, 1, 2},
+		{"a a a a", "a a", 0, 0, 2},
+	}
+	for _, tc := range tests {
+		as, bs := strings.Fields(tc.a), strings.Fields(tc.b)
+		i, j, n := slice.LCString(as, bs)
+		if i != tc.i || j != tc.j || n != tc.n {
+			t.Errorf("LCString(%q, %q): got (%d, %d, %d), want (%d, %d, %d)",
+				tc.a, tc.b, i, j, n, tc.i, tc.j, tc.n)
+		}
+	}
+}
+
+func TestIndex(t *testing.T) {
+	tests := []struct {
+		hay, needle string
+		want        []int // all non-overlapping matches
+	}{
+		{"", "", nil},
+		{"a b c", "", nil},
+		{"", "a", nil},
+		{"a", "a b", nil},
+		{"a b c", "d", nil},
+		{"a b c", "a b c", []int{0}},
+		{"a b c", "b", []int{1}},
+		{"a b a b a", "a b", []int{0, 2}},
+		{"a a a a", "a a", []int{0, 2}},
+		{"a a a", "a a", []int{0}},
+		{"a b a b a c", "a b a c", []int{2}},
+		{"x a a b a a a b", "a a b", []int{1, 5}},
+		{"a b c a b d a b c", "a b c", []int{0, 6}},
+	}
+	for _, tc := range tests {
+		hay, needle := strings.Fields(tc.hay), strings.Fields(tc.needle)
+		want := -1
+		if len(tc.want) != 0 {
+			want = tc.want[0]
+		} else if len(needle) == 0 {
+			want = 0
+		}
+		if got := slice.Index(hay, needle); got != want {
+			t.Errorf("Index(%q, %q): got %d, want %d", tc.hay, tc.needle, got, want)
+		}
+		if got := slice.IndexAll(hay, needle); !slices.Equal(got, tc.want) {
+			t.Errorf("IndexAll(%q, %q): got %v, want %v", tc.hay, tc.needle, got, tc.want)
+		}
+	}
+
+	// The Func variants use the equivalence provided.
+	hay := []string{"x", "A", "b", "a", "B"}
+	if got := slice.IndexAllFunc(hay, []string{"a", "b"}, strings.EqualFold); !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("IndexAllFunc: got %v, want [1 3]", got)
+	}
+	if got := slice.IndexFunc(hay, []string{"B"}, strings.EqualFold); got != 2 {
+		t.Errorf("IndexFunc: got %d, want 2", got)
+	}
+}
+
+func TestIndexRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	for range 500 {
+		hay := make([]byte, rng.IntN(60))
+		for i := range hay {
+			hay[i] = 'a' + byte(rng.IntN(3))
+		}
+		needle := make([]byte, 1+rng.IntN(4))
+		for i := range needle {
+			needle[i] = 'a' + byte(rng.IntN(3))
+		}
+
+		// Check against the standard library on strings.
+		hs, ns := string(hay), string(needle)
+		if got, want := slice.Index(hay, needle), strings.Index(hs, ns); got != want {
+			t.Errorf("Index(%q, %q): got %d, want %d", hs, ns, got, want)
+		}
+		var want []int
+		for off := 0; ; {
+			i := strings.Index(hs[off:], ns)
+			if i < 0 {
+				break
+			}
+			want = append(want, off+i)
+			off += i + len(ns)
+		}
+		if got := slice.IndexAll(hay, needle); !slices.Equal(got, want) {
+			t.Errorf("IndexAll(%q, %q): got %v, want %v", hs, ns, got, want)
+		}
+		if got, want := len(slice.IndexAll(hay, needle)), strings.Count(hs, ns); got != want {
+			t.Errorf("IndexAll(%q, %q): got %d matches, want %d", hs, ns, got, want)
+		}
+	}
+}
+
+func TestLCStringRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	randInput := func(n int) []byte {
+		out := make([]byte, n)
+		for i := range out {
+			out[i] = "abc"[rng.IntN(3)]
+		}
+		return out
+	}
+	for range 500 {
+		as, bs := randInput(rng.IntN(30)), randInput(rng.IntN(30))
+		i, j, n := slice.LCString(as, bs)
+		if !slices.Equal(as[i:i+n], bs[j:j+n]) {
+			t.Fatalf("LCString(%q, %q): got %q at %d, %q at %d", as, bs, as[i:i+n], i, bs[j:j+n], j)
+		}
+		if want := bruteLCString(as, bs); n != want {
+			t.Errorf("LCString(%q, %q): got length %d, want %d", as, bs, n, want)
+		}
+	}
+}
+
+// bruteLCString returns the length of a longest common substring of as and
+// bs by exhaustive comparison.
+func bruteLCString(as, bs []byte) int {
+	var best int
+	for i := range as {
+		for j := range bs {
+			n := 0
+			for i+n < len(as) && j+n < len(bs) && as[i+n] == bs[j+n] {
+				n++
+			}
+			best = max(best, n)
+		}
+	}
+	return best
+}
@@ -0,0 +1,94 @@
+package stree
+
+import "github.com/creachadair/mds/internal/augment"
+
+func init() {
+	augment.Set = func(tree, fn any) {
+		tree.(interface{ setAugment(any) }).setAugment(fn)
+	}
+}
+
+// setAugment installs fn, which must be a func(x, left, right *T), as the
+// augmentation function of t. See [augment.Set].
+func (t *Tree[T]) setAugment(fn any) {
+	t.aug = fn.(func(x, left, right *T))
+	t.fixAll(t.root)
+}
+
+// fix updates the augmented data of n from its children.
+// Precondition: t.aug != nil.
+func (t *Tree[T]) fix(n *node[T]) {
+	var left, right *T
+	if n.left != nil {
+		left = &n.left.X
+	}
+	if n.right != nil {
+		right = &n.right.X
+	}
+	t.aug(&n.X, left, right)
+}
+
+// fixAll updates the augmented data of all the nodes in the subtree under n,
+// if t has an augmentation function.
+func (t *Tree[T]) fixAll(n *node[T]) {
+	if t.aug != nil && n != nil {
+		t.fixAll(n.left)
+		t.fixAll(n.right)
+		t.fix(n)
+	}
+}
+
+// fixPath updates the augmented data of the nodes in path, from last to first.
+// Precondition: t.aug != nil.
+func (t *Tree[T]) fixPath(path []*node[T]) {
+	for i := len(path) - 1; i >= 0; i-- {
+		t.fix(path[i])
+	}
+}
+
+// fixSpine updates the augmented data of the nodes on the left (if left is
+// true) or right spine of the subtree under n, from the bottom up.
+// Precondition: t.aug != nil.
+func (t *Tree[T]) fixSpine(n *node[T], left bool) {
+	var buf [64]*node[T]
+	spine := buf[:0]
+	for cur := n; cur != nil; {
+		spine = append(spine, cur)
+		if left {
+			cur = cur.left
+		} else {
+			cur = cur.right
+		}
+	}
+	t.fixPath(spine)
+}
+
+// rewrite rebalances the subtree under root, which has the given size, and
+// returns the new root of the subtree, with its augmented data updated.
+func (t *Tree[T]) rewrite(root *node[T], size int) *node[T] {
+	root = rewrite(root, size)
+	t.fixAll(root)
+	return root
+}
+
+// removeAugmented removes key from t, updating the augmented data of the nodes
+// affected, and reports whether key was present.
+// Precondition: t.aug != nil.
+func (t *Tree[T]) removeAugmented(key T) bool {
+	path := t.root.pathTo(key, t.compare)
+	if len(path) == 0 || t.compare(path[len(path)-1].X, key) != 0 {
+		return false
+	}
+	target := path[len(path)-1]
+	twoChild := target.left != nil && target.right != nil
+
+	t.root, _ = t.root.remove(key, t.compare)
+	if twoChild {
+		// The key of target was replaced by its successor, which was removed
+		// from the left spine of target's right subtree.
+		t.fixSpine(target.right, true)
+		t.fix(target)
+	}
+	t.fixPath(path[:len(path)-1])
+	return true
+}
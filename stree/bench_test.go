@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand/v2"
+	"slices"
 	"sort"
 	"testing"
 
@@ -34,6 +35,25 @@ func BenchmarkNew(b *testing.B) {
 	}
 }
 
+func BenchmarkNewSorted(b *testing.B) {
+	for _, n := range []int{1000, 100000} {
+		values := make([]int, n)
+		for i := range values {
+			values[i] = i
+		}
+		b.Run(fmt.Sprintf("New/n=%d", n), func(b *testing.B) {
+			for range b.N {
+				stree.New(250, intCompare, values...)
+			}
+		})
+		b.Run(fmt.Sprintf("NewSorted/n=%d", n), func(b *testing.B) {
+			for range b.N {
+				stree.NewSorted(250, intCompare, values...)
+			}
+		})
+	}
+}
+
 func BenchmarkAddRandom(b *testing.B) {
 	for _, β := range balances {
 		b.Run(fmt.Sprintf("β=%d", β), func(b *testing.B) {
@@ -47,6 +67,17 @@ func BenchmarkAddRandom(b *testing.B) {
 	}
 }
 
+func BenchmarkReplaceAll(b *testing.B) {
+	for _, β := range balances {
+		b.Run(fmt.Sprintf("β=%d", β), func(b *testing.B) {
+			_, values := randomTree(b, β)
+			b.ResetTimer()
+			tree := stree.New[int](β, intCompare)
+			tree.ReplaceAll(slices.Values(values))
+		})
+	}
+}
+
 func BenchmarkAddOrdered(b *testing.B) {
 	for _, β := range balances {
 		b.Run(fmt.Sprintf("β=%d", β), func(b *testing.B) {
@@ -5,7 +5,17 @@ import "slices"
 // A Cursor is an anchor to a location within a Tree that can be used to
 // navigate the structure of the tree. A cursor is Valid if it points to a
 // non-empty subtree of its tree.
+//
+// If keys are added to or removed from the tree while a cursor is in use, the
+// cursor finds the location of its current key again the next time it is
+// used, so it remains valid and points to the same key. If its key was
+// removed, the cursor becomes invalid, unless the removal moved another key
+// into the same node of the tree, in which case the cursor points to that key.
+// Cursors into a tree are not preserved by [Tree.ClearReuse].
 type Cursor[T any] struct {
+	tree *Tree[T] // the tree containing the cursor
+	mods uint64   // the modification count of tree when path was found
+
 	// The sequence of nodes from the root to the current item.
 	// The pointers are shared with the underlying tree.
 	// If this is empty, the cursor is invalid.
@@ -14,7 +24,29 @@ type Cursor[T any] struct {
 
 // Valid reports whether c is a valid cursor, meaning it points to a non-empty
 // subtree of its containing tree. A nil Cursor is treated as invalid.
-func (c *Cursor[T]) Valid() bool { return c != nil && len(c.path) != 0 }
+func (c *Cursor[T]) Valid() bool {
+	if c == nil || len(c.path) == 0 {
+		return false
+	}
+	if c.mods != c.tree.mods {
+		c.reseek()
+	}
+	return len(c.path) != 0
+}
+
+// reseek finds the path to the key of c again after its tree has been
+// structurally modified. If the key is no longer in the tree, c becomes
+// invalid.
+//
+// Precondition: c is non-empty.
+func (c *Cursor[T]) reseek() {
+	key := c.path[len(c.path)-1].X
+	c.path = c.tree.root.pathTo(key, c.tree.compare)
+	if n := len(c.path); n != 0 && c.tree.compare(c.path[n-1].X, key) != 0 {
+		c.path = nil
+	}
+	c.mods = c.tree.mods
+}
 
 // Clone returns a clone of c that points to the same location, but which is
 // unaffected by subsequent movement of c (and vice versa).
@@ -22,7 +54,7 @@ func (c *Cursor[T]) Clone() *Cursor[T] {
 	if !c.Valid() {
 		return c
 	}
-	return &Cursor[T]{path: slices.Clone(c.path)}
+	return &Cursor[T]{tree: c.tree, path: slices.Clone(c.path), mods: c.mods}
 }
 
 // Key returns the key at the current location of the cursor.
@@ -235,6 +267,99 @@ func (c *Cursor[T]) Max() *Cursor[T] {
 // Inorder is a range function over each key of the subtree at c in order.
 func (c *Cursor[T]) Inorder(yield func(key T) bool) {
 	if c.Valid() {
-		c.path[len(c.path)-1].inorder(yield)
+		c.path[len(c.path)-1].inorder(c.tree.guard(yield))
+	}
+}
+
+// Set replaces the key at the current location of c with key, and reports
+// whether it did so. The key must be equivalent to the current key, so that
+// the order of the tree is unchanged; this is useful to update the value of
+// a key-value pair in place. If c is invalid or key is not equivalent to the
+// current key, Set returns false without modifying the tree.
+func (c *Cursor[T]) Set(key T) bool {
+	if !c.Valid() {
+		return false
+	}
+	cur := c.path[len(c.path)-1]
+	if c.tree.compare(cur.X, key) != 0 {
+		return false
+	}
+	cur.X = key
+	if t := c.tree; t.aug != nil {
+		t.fixPath(c.path)
+	}
+	return true
+}
+
+// Delete removes the key at the current location of c from its tree, moves c
+// to the successor of that key, and returns c. If the key had no successor, c
+// becomes invalid. If c is invalid, Delete does nothing. Other cursors into
+// the tree are updated as described for [Cursor].
+//
+// Unlike deleting the key with [Tree.Remove], Delete does not need to search
+// the tree for the key or its successor, making it useful to remove keys
+// while traversing the tree.
+func (c *Cursor[T]) Delete() *Cursor[T] {
+	if !c.Valid() {
+		return c
 	}
+	t := c.tree
+	i := len(c.path) - 1
+	cur := c.path[i]
+	anc := c.path[:i] // not modified below, even if c.path is
+	for _, n := range anc {
+		n.n--
+	}
+
+	if cur.left != nil && cur.right != nil {
+		// Move the successor's key into cur, and remove the successor from the
+		// right subtree. The cursor now points to the successor's key.
+		goat := popMinRight(cur)
+		cur.X = goat.X
+		cur.n--
+		if t.aug != nil {
+			t.fixSpine(cur.right, true)
+			t.fix(cur)
+		}
+	} else {
+		// Splice cur out of the tree, replacing it with its only child (if any).
+		// If cur has a right child, the successor is the minimum of that
+		// subtree; otherwise it is an ancestor of cur (or does not exist).
+		next, j := c.findNext()
+		child := cur.left
+		if child == nil {
+			child = cur.right
+		}
+		if i == 0 {
+			t.root = child
+		} else if par := c.path[i-1]; par.left == cur {
+			par.left = child
+		} else {
+			par.right = child
+		}
+		cur.left, cur.right = nil, nil
+
+		if next != nil {
+			c.path = c.path[:i]
+			for ; next != nil; next = next.left {
+				c.path = append(c.path, next)
+			}
+		} else if j >= 0 {
+			c.path = c.path[:j+1]
+		} else {
+			c.path = nil
+		}
+	}
+
+	if t.aug != nil {
+		t.fixPath(anc)
+	}
+
+	// If removing the key caused the tree to be rebuilt, the path to the
+	// successor has to be found again.
+	if t.decSize() && len(c.path) != 0 {
+		c.path = t.root.pathTo(c.path[len(c.path)-1].X, t.compare)
+	}
+	c.mods = t.mods
+	return c
 }
@@ -0,0 +1,99 @@
+package stree
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"slices"
+)
+
+// MarshalJSON implements the [json.Marshaler] interface. The keys of t are
+// encoded as a JSON array in order.
+func (t *Tree[T]) MarshalJSON() ([]byte, error) { return json.Marshal(t.keys()) }
+
+// UnmarshalJSON implements the [json.Unmarshaler] interface. It decodes a JSON
+// array of keys and replaces the contents of t with them. The tree must have
+// been constructed with New, so that it has a comparison function.
+//
+// If the keys are in order without duplicates, as written by MarshalJSON, the
+// tree is rebuilt in time proportional to the number of keys. Otherwise, the
+// keys are sorted and duplicates are discarded as for New.
+func (t *Tree[T]) UnmarshalJSON(data []byte) error {
+	if t.compare == nil {
+		return errors.New("stree: cannot decode into an uninitialized Tree")
+	}
+	var keys []T
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return err
+	}
+	t.load(keys)
+	return nil
+}
+
+// GobEncode implements the [gob.GobEncoder] interface. The keys of t are
+// encoded as a slice in order.
+func (t *Tree[T]) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(t.keys()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements the [gob.GobDecoder] interface. It replaces the
+// contents of t with the decoded keys, in the same manner as UnmarshalJSON.
+func (t *Tree[T]) GobDecode(data []byte) error {
+	if t.compare == nil {
+		return errors.New("stree: cannot decode into an uninitialized Tree")
+	}
+	var keys []T
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&keys); err != nil {
+		return err
+	}
+	t.load(keys)
+	return nil
+}
+
+// keys returns a slice of the keys of t in order. The result is never nil, so
+// that an empty tree encodes as an empty array.
+func (t *Tree[T]) keys() []T {
+	out := make([]T, 0, t.size)
+	for key := range t.Inorder {
+		out = append(out, key)
+	}
+	return out
+}
+
+// load replaces the contents of t with a balanced tree of the given keys.
+// If the keys are not strictly increasing, they are sorted and duplicates are
+// discarded, keeping the first of each.
+func (t *Tree[T]) load(keys []T) {
+	nodes := make([]*node[T], len(keys))
+	for i, key := range keys {
+		nodes[i] = &node[T]{X: key, n: 1}
+	}
+	if !isStrictlyIncreasing(keys, t.compare) {
+		slices.SortStableFunc(nodes, func(a, b *node[T]) int {
+			return t.compare(a.X, b.X)
+		})
+		nodes = slices.CompactFunc(nodes, func(a, b *node[T]) bool {
+			return t.compare(a.X, b.X) == 0
+		})
+	}
+	t.root = extract(nodes)
+	t.fixAll(t.root)
+	t.size = len(nodes)
+	t.max = t.size
+	t.mods++
+}
+
+// isStrictlyIncreasing reports whether each key is greater than the previous.
+func isStrictlyIncreasing[T any](keys []T, compare func(a, b T) int) bool {
+	for i := 1; i < len(keys); i++ {
+		if compare(keys[i-1], keys[i]) >= 0 {
+			return false
+		}
+	}
+	return true
+}
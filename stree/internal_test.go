@@ -3,7 +3,10 @@ package stree
 import (
 	"cmp"
 	"math"
+	"math/rand/v2"
 	"testing"
+
+	"github.com/creachadair/mds/internal/augment"
 )
 
 func TestVine(t *testing.T) {
@@ -59,9 +62,141 @@ func TestVine(t *testing.T) {
 	})
 }
 
-func (n *node[T]) height() int {
+func TestSizes(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	for _, β := range []int{0, 250, 1000} {
+		tree := New(β, cmp.Compare[int])
+		for i := range 1000 {
+			key := rng.IntN(500)
+			switch rng.IntN(13) {
+			case 0, 1, 2:
+				tree.Add(key)
+			case 3, 4, 5:
+				tree.Remove(key)
+			case 6:
+				tree.AddAll(key, key+3, key+5)
+			case 7:
+				tree.RemoveAll(key, key+1, key+3, key+5)
+			case 8:
+				tree.Merge(New(β, cmp.Compare[int], key+1, key+2, key+4))
+			case 9:
+				tree.Merge(tree.Split(key))
+			case 10:
+				if c := tree.Find(key); c != nil {
+					c.Delete()
+				}
+			case 11:
+				if key%2 == 0 {
+					tree.PopMin()
+				} else {
+					tree.PopMax()
+				}
+			default:
+				tree.ReplaceAll(func(yield func(int) bool) {
+					for i := range rng.IntN(10) {
+						if !yield(key + 7*i) {
+							return
+						}
+					}
+				})
+			}
+			if got := checkSizes(t, tree.root); got != tree.Len() {
+				t.Fatalf("β=%d step %d: root size %d, want %d", β, i, got, tree.Len())
+			}
+		}
+	}
+}
+
+// checkSizes reports an error for each node under n whose recorded size does
+// not match the number of nodes in its subtree, and returns the actual size.
+func checkSizes[T any](t *testing.T, n *node[T]) int {
+	t.Helper()
 	if n == nil {
 		return 0
 	}
-	return max(n.left.height(), n.right.height()) + 1
+	want := 1 + checkSizes(t, n.left) + checkSizes(t, n.right)
+	if n.n != want {
+		t.Errorf("Node %v: got size %d, want %d", n.X, n.n, want)
+	}
+	return want
+}
+
+func TestAugment(t *testing.T) {
+	// Each key records the sum of the keys in its subtree.
+	type sumKey struct{ key, sum int }
+	sum := func(x, left, right *sumKey) {
+		x.sum = x.key
+		if left != nil {
+			x.sum += left.sum
+		}
+		if right != nil {
+			x.sum += right.sum
+		}
+	}
+	var check func(n *node[sumKey]) int
+	check = func(n *node[sumKey]) int {
+		if n == nil {
+			return 0
+		}
+		want := n.X.key + check(n.left) + check(n.right)
+		if n.X.sum != want {
+			t.Errorf("Node %d: got sum %d, want %d", n.X.key, n.X.sum, want)
+		}
+		return want
+	}
+
+	rng := rand.New(rand.NewPCG(3, 4))
+	for _, β := range []int{0, 250, 1000} {
+		tree := New(β, func(a, b sumKey) int { return cmp.Compare(a.key, b.key) })
+		for i := range 50 {
+			tree.Add(sumKey{key: i}) // added before the augmentation is set
+		}
+		augment.Set(tree, sum)
+		check(tree.root)
+
+		for i := range 2000 {
+			k := rng.IntN(500)
+			key := sumKey{key: k}
+			switch rng.IntN(14) {
+			case 0, 1, 2:
+				tree.Add(key)
+			case 3, 4, 5:
+				tree.Remove(key)
+			case 6:
+				tree.AddAll(key, sumKey{key: k + 3}, sumKey{key: k + 5})
+			case 7:
+				tree.RemoveAll(key, sumKey{key: k + 1}, sumKey{key: k + 3})
+			case 8:
+				tree.Merge(New(β, tree.compare, sumKey{key: k + 1}, sumKey{key: k + 2}))
+			case 9:
+				hi := tree.Split(key)
+				check(hi.root)
+				tree.Merge(hi)
+			case 10:
+				if c := tree.Find(key); c != nil {
+					c.Delete()
+				}
+			case 11:
+				if k%2 == 0 {
+					tree.PopMin()
+				} else {
+					tree.PopMax()
+				}
+			case 12:
+				tree.RemoveIf(func(x sumKey) bool { return x.key%7 == k%7 })
+			default:
+				tree.Replace(key)
+				if c := tree.Cursor(key); c.Valid() {
+					c.Set(key)
+				}
+			}
+			check(tree.root)
+			if t.Failed() {
+				t.Fatalf("β=%d step %d: augmented data are incorrect", β, i)
+			}
+		}
+		tree.Clear()
+		tree.Add(sumKey{key: 1})
+		check(tree.root)
+	}
 }
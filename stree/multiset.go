@@ -0,0 +1,139 @@
+package stree
+
+import "iter"
+
+// A Multiset is an ordered collection of keys that, unlike a [Tree], may
+// contain multiple keys that are equivalent under its comparison function.
+// Equivalent keys are grouped together in a single node of an underlying
+// scapegoat tree, so the cost of lookups and rebalancing depends on the number
+// of distinct keys rather than the total number of keys.
+//
+// Within each group of equivalent keys, the keys are kept in the order they
+// were added. A *Multiset is not safe for concurrent use without external
+// synchronization.
+type Multiset[T any] struct {
+	tree *Tree[group[T]]
+	size int // total number of keys, including duplicates
+}
+
+// A group is a non-empty collection of equivalent keys. The first key is
+// used for comparison.
+type group[T any] struct {
+	X    T   // the first key added
+	more []T // additional equivalent keys, in order of addition
+}
+
+func (g group[T]) len() int { return 1 + len(g.more) }
+
+// NewMultiset returns a new multiset with the given balancing factor and
+// comparison function, as for [New]. If any keys are given, the multiset is
+// initialized to contain them, including any duplicates.
+//
+// NewMultiset panics if β < 0 or β > 1000.
+func NewMultiset[T any](β int, compare func(a, b T) int, keys ...T) *Multiset[T] {
+	m := &Multiset[T]{
+		tree: New(β, func(a, b group[T]) int { return compare(a.X, b.X) }),
+	}
+	for _, key := range keys {
+		m.Add(key)
+	}
+	return m
+}
+
+// Add adds key to m, and reports the number of keys equivalent to key that m
+// contains after the addition, including key itself.
+func (m *Multiset[T]) Add(key T) int {
+	var count int
+	m.tree.upsert(group[T]{X: key}, func(n *node[group[T]], _ group[T], added bool) {
+		if !added {
+			n.X.more = append(n.X.more, key)
+		}
+		count = n.X.len()
+	})
+	m.size++
+	return count
+}
+
+// CountOf reports the number of keys in m equivalent to key.
+func (m *Multiset[T]) CountOf(key T) int {
+	if n := m.tree.lookup(group[T]{X: key}); n != nil {
+		return n.X.len()
+	}
+	return 0
+}
+
+// RemoveOne removes the most recently added key equivalent to key from m, and
+// reports whether any such key was present.
+func (m *Multiset[T]) RemoveOne(key T) bool {
+	n := m.tree.lookup(group[T]{X: key})
+	if n == nil {
+		return false
+	}
+	if last := len(n.X.more) - 1; last >= 0 {
+		var zero T
+		n.X.more[last] = zero
+		n.X.more = n.X.more[:last]
+	} else {
+		m.tree.Remove(n.X)
+	}
+	m.size--
+	return true
+}
+
+// RemoveAll removes all the keys equivalent to key from m, and reports the
+// number of keys removed.
+func (m *Multiset[T]) RemoveAll(key T) int {
+	n := m.tree.lookup(group[T]{X: key})
+	if n == nil {
+		return 0
+	}
+	count := n.X.len()
+	m.tree.Remove(n.X)
+	m.size -= count
+	return count
+}
+
+// Len reports the number of keys in m, including duplicates.
+func (m *Multiset[T]) Len() int { return m.size }
+
+// IsEmpty reports whether m is empty.
+func (m *Multiset[T]) IsEmpty() bool { return m.size == 0 }
+
+// Distinct reports the number of distinct keys in m, that is, the number of
+// groups of equivalent keys.
+func (m *Multiset[T]) Distinct() int { return m.tree.Len() }
+
+// Clear discards all the keys in m, leaving it empty.
+func (m *Multiset[T]) Clear() { m.tree.Clear(); m.size = 0 }
+
+// Inorder is a range function that visits each key of m in order. Equivalent
+// keys are visited in the order they were added.
+func (m *Multiset[T]) Inorder(yield func(key T) bool) {
+	m.tree.Inorder(func(g group[T]) bool { return g.each(yield) })
+}
+
+// Between returns a range function for each key greater than or equal to lo
+// and less than hi, in order, as for [Tree.Between].
+func (m *Multiset[T]) Between(lo, hi T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for g := range m.tree.Between(group[T]{X: lo}, group[T]{X: hi}) {
+			if !g.each(yield) {
+				return
+			}
+		}
+	}
+}
+
+// each calls f for each key of g in order until f returns false, and reports
+// whether all the keys were visited.
+func (g group[T]) each(f func(T) bool) bool {
+	if !f(g.X) {
+		return false
+	}
+	for _, key := range g.more {
+		if !f(key) {
+			return false
+		}
+	}
+	return true
+}
@@ -3,6 +3,7 @@ package stree
 type node[T any] struct {
 	X           T
 	left, right *node[T]
+	n           int // number of nodes in the subtree rooted here
 }
 
 // clone returns a deep copy of n.
@@ -10,7 +11,7 @@ func (n *node[T]) clone() *node[T] {
 	if n == nil {
 		return nil
 	}
-	return &node[T]{X: n.X, left: n.left.clone(), right: n.right.clone()}
+	return &node[T]{X: n.X, left: n.left.clone(), right: n.right.clone(), n: n.n}
 }
 
 // size reports the number of nodes contained in the tree rooted at n.
@@ -19,7 +20,17 @@ func (n *node[T]) size() int {
 	if n == nil {
 		return 0
 	}
-	return 1 + n.left.size() + n.right.size()
+	return n.n
+}
+
+// resize recomputes the subtree sizes of all the nodes in the tree rooted at
+// n, and returns the size of n.
+func (n *node[T]) resize() int {
+	if n == nil {
+		return 0
+	}
+	n.n = 1 + n.left.resize() + n.right.resize()
+	return n.n
 }
 
 // treeToVine rewrites the tree rooted at n into an inorder linked list, and
@@ -86,7 +97,8 @@ func rotateLeft[T any](n *node[T], count int) {
 
 // vineToTree rewrites the chain of count nodes starting from n and linked by
 // right pointers, into a balanced tree rooted at n. It returns the root of the
-// resulting new tree. It will panic if count exceeds the chain length.
+// resulting new tree, with its subtree sizes updated. It will panic if count
+// exceeds the chain length.
 //
 // This uses Stout & Warren's extension of Day's algorithm that produces a tree
 // that is "full" (as much as possible), with leaves filled left-to-right.
@@ -113,11 +125,13 @@ func vineToTree[T any](n *node[T], count int) *node[T] {
 		left /= 2
 		rotateLeft(stub, left)
 	}
+	stub.right.resize()
 	return stub.right
 }
 
 // extract constructs a balanced tree from the given nodes and returns the root
-// of the tree. The child pointers of the resulting nodes are updated in place.
+// of the tree. The child pointers and sizes of the resulting nodes are updated
+// in place.
 // This function does not allocate on the heap. The nodes must be
 // sorted and free of duplicates.
 func extract[T any](nodes []*node[T]) *node[T] {
@@ -128,6 +142,7 @@ func extract[T any](nodes []*node[T]) *node[T] {
 	root := nodes[mid]
 	root.left = extract(nodes[:mid])
 	root.right = extract(nodes[mid+1:])
+	root.n = len(nodes)
 	return root
 }
 
@@ -139,11 +154,13 @@ func rewrite[T any](root *node[T], size int) *node[T] {
 }
 
 // popMinRight removes the smallest node from the right subtree of root,
-// modifying the tree in-place and returning the node removed.
-// This function panics if root == nil or root.right == nil.
+// modifying the tree in-place and returning the node removed. The sizes of the
+// nodes in the right subtree are updated, but the caller must update the size
+// of root. This function panics if root == nil or root.right == nil.
 func popMinRight[T any](root *node[T]) *node[T] {
 	par, goat := root, root.right
 	for goat.left != nil {
+		goat.n--
 		par, goat = goat, goat.left
 	}
 	if par == root {
@@ -153,9 +170,32 @@ func popMinRight[T any](root *node[T]) *node[T] {
 	}
 	goat.left = nil
 	goat.right = nil
+	goat.n = 1
 	return goat
 }
 
+// height returns the number of nodes on a longest path from n to a leaf.
+func (n *node[T]) height() int {
+	if n == nil {
+		return 0
+	}
+	return max(n.left.height(), n.right.height()) + 1
+}
+
+// depths adds to hist the count of nodes at each depth of the subtree under
+// n, where n is at depth d, and returns the updated histogram.
+func (n *node[T]) depths(d int, hist []int) []int {
+	if n == nil {
+		return hist
+	}
+	if d == len(hist) {
+		hist = append(hist, 0)
+	}
+	hist[d]++
+	hist = n.left.depths(d+1, hist)
+	return n.right.depths(d+1, hist)
+}
+
 // inorder visits the subtree under n inorder, calling f until f returns false.
 func (n *node[T]) inorder(f func(T) bool) bool {
 	for n != nil {
@@ -169,6 +209,20 @@ func (n *node[T]) inorder(f func(T) bool) bool {
 	return true
 }
 
+// reverseInorder visits the subtree under n in reverse order, calling f until
+// f returns false.
+func (n *node[T]) reverseInorder(f func(T) bool) bool {
+	for n != nil {
+		if ok := n.right.reverseInorder(f); !ok {
+			return false
+		} else if ok := f(n.X); !ok {
+			return false
+		}
+		n = n.left
+	}
+	return true
+}
+
 // pathTo returns the sequence of nodes beginning at n leading to key, if key
 // is present. If key was found, its node is the last element of the path.
 func (n *node[T]) pathTo(key T, compare func(a, b T) int) []*node[T] {
@@ -206,3 +260,36 @@ func (n *node[T]) inorderAfter(key T, compare func(a, b T) int, f func(T) bool)
 	}
 	return true
 }
+
+// between visits the elements of the subtree under n that are greater than or
+// equal to lo and less than hi inorder, calling f for each until f returns
+// false. Subtrees entirely outside the range are not visited.
+func (n *node[T]) between(lo, hi T, compare func(a, b T) int, f func(T) bool) bool {
+	if n == nil {
+		return true
+	} else if compare(n.X, lo) < 0 {
+		return n.right.between(lo, hi, compare, f)
+	} else if compare(n.X, hi) >= 0 {
+		return n.left.between(lo, hi, compare, f)
+	}
+	return n.left.between(lo, hi, compare, f) && f(n.X) && n.right.between(lo, hi, compare, f)
+}
+
+// inorderBefore visits the elements of the subtree under n not greater than
+// key in reverse order, calling f for each until f returns false.
+func (n *node[T]) inorderBefore(key T, compare func(a, b T) int, f func(T) bool) bool {
+	// Find the path from the root to key. Any nodes less than or equal to key
+	// must be on or to the left of this path.
+	path := n.pathTo(key, compare)
+	for i := len(path) - 1; i >= 0; i-- {
+		cur := path[i]
+		if compare(cur.X, key) > 0 {
+			continue
+		} else if ok := f(cur.X); !ok {
+			return false
+		} else if ok := cur.left.reverseInorder(f); !ok {
+			return false
+		}
+	}
+	return true
+}
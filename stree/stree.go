@@ -5,11 +5,13 @@
 // insertion and deletion (the worst-case cost of a single insert or delete
 // operation is O(n)).
 //
-// Scapegoat trees are relatively memory-efficient, as interior nodes do not
-// require any ancillary metadata for balancing purposes, and the tree itself
-// costs only a few words of bookkeeping overhead beyond the nodes. Rebalancing
-// uses the Day-Stout-Warren (DSW) in-place algorithm, which does not require
-// any additional heap allocations.
+// Scapegoat trees are relatively memory-efficient: besides its key and child
+// pointers, each node carries a single int recording the size of its subtree,
+// and the tree itself costs only a few words of bookkeeping overhead beyond
+// the nodes. The subtree sizes are used to find a scapegoat when the tree
+// becomes unbalanced, and to support order statistics (Rank and Select) in
+// O(lg n) time. Rebalancing uses the Day-Stout-Warren (DSW) in-place
+// algorithm, which does not require any additional heap allocations.
 //
 // The scapegoat tree algorithm is described by the paper:
 //
@@ -21,6 +23,7 @@ import (
 	"fmt"
 	"iter"
 	"math"
+	"math/bits"
 	"slices"
 )
 
@@ -56,7 +59,7 @@ func New[T any](β int, compare func(a, b T) int, keys ...T) *Tree[T] {
 	if len(keys) != 0 {
 		nodes := make([]*node[T], len(keys))
 		for i, key := range keys {
-			nodes[i] = &node[T]{X: key}
+			nodes[i] = &node[T]{X: key, n: 1}
 		}
 		slices.SortFunc(nodes, func(a, b *node[T]) int {
 			return compare(a.X, b.X)
@@ -71,8 +74,41 @@ func New[T any](β int, compare func(a, b T) int, keys ...T) *Tree[T] {
 	return tree
 }
 
+// NewSorted returns a new tree with the given balancing factor and comparison
+// function, as for [New], initialized to contain the given keys. Unlike New,
+// NewSorted requires the keys to be strictly increasing under compare, that
+// is, in order and free of duplicates, and builds the tree from them directly
+// without sorting. This makes it cheaper to load a large collection of keys
+// that are already in order, such as a snapshot of another tree.
+//
+// NewSorted checks the order of the keys, which takes n-1 comparisons for n
+// keys, and panics if they are not strictly increasing. It also panics if
+// β < 0 or β > 1000.
+func NewSorted[T any](β int, compare func(a, b T) int, keys ...T) *Tree[T] {
+	tree := New(β, compare)
+	if !isStrictlyIncreasing(keys, compare) {
+		panic("keys are not strictly increasing")
+	}
+	if len(keys) != 0 {
+		nodes := make([]*node[T], len(keys))
+		for i, key := range keys {
+			nodes[i] = &node[T]{X: key, n: 1}
+		}
+		tree.size = len(nodes)
+		tree.max = len(nodes)
+		tree.root = extract(nodes)
+	}
+	return tree
+}
+
 // A Tree is the root of a scapegoat tree. A *Tree is not safe for concurrent
 // use without external synchronization.
+//
+// Adding or removing keys may change the structure of the tree. The iteration
+// methods of a Tree panic if the tree is structurally modified while they are
+// running; replacing a key with an equivalent one is not a structural change.
+// A [Cursor] finds its key again if the tree is structurally modified while it
+// is in use; see [Cursor] for details.
 type Tree[T any] struct {
 	root *node[T]
 
@@ -87,6 +123,15 @@ type Tree[T any] struct {
 	limit   func(n int) int  // depth limit for size n
 	size    int              // cache of root.size()
 	max     int              // max of size since last rebuild of root
+	mods    uint64           // count of structural modifications
+
+	// If non-nil, aug updates the augmented data of a key from the keys of
+	// its children; see augment.go.
+	aug func(x, left, right *T)
+
+	// Nodes retained by ClearReuse for later insertions, linked by their
+	// right pointers.
+	free *node[T]
 }
 
 func toFraction(β int) float64 { return (float64(β) + maxBalance) / fracLimit }
@@ -107,119 +152,397 @@ func limitFunc(β int) func(int) int {
 func (t *Tree[T]) Clone() *Tree[T] {
 	cp := *t                 // shallow copy of the top-level structures
 	cp.root = t.root.clone() // deep copy of the contents
+	cp.free = nil            // do not share retained nodes
 	return &cp
 }
 
+// Equal reports whether t and other contain equivalent keys, as determined by
+// the comparison function of t. This takes O(n) time, but reports false at
+// once if t and other have different lengths.
+func (t *Tree[T]) Equal(other *Tree[T]) bool {
+	return t.size == other.size && t.Compare(other) == 0
+}
+
+// Compare compares the keys of t and other lexicographically in order, using
+// the comparison function of t. It returns -1 if t < other, 0 if t == other,
+// and +1 if t > other. If the keys of one tree are a prefix of the other, the
+// shorter tree is less.
+func (t *Tree[T]) Compare(other *Tree[T]) int {
+	next, stop := iter.Pull(other.Inorder)
+	defer stop()
+	for key := range t.Inorder {
+		okey, ok := next()
+		if !ok {
+			return 1
+		} else if c := t.compare(key, okey); c != 0 {
+			return max(-1, min(c, 1))
+		}
+	}
+	if _, ok := next(); ok {
+		return -1
+	}
+	return 0
+}
+
 // Add inserts key into the tree. If key is already present, Add returns false
 // without modifying the tree. Otherwise it adds the key and returns true.
-func (t *Tree[T]) Add(key T) bool {
-	// We don't yet know whether the insertion will add mass to the tree; we
-	// conservatively assume it might for purposes of choosing a depth limit.
-	ins, ok, _, _ := t.insert(key, false, t.root, t.limit(t.size+1))
-	t.incSize(ok)
-	t.root = ins
-	return ok
-}
+func (t *Tree[T]) Add(key T) bool { return t.upsert(key, nil) }
 
 // Replace inserts key into the tree. If key is already present, Replace
 // updates the existing value and returns false. Otherwise it adds key and
 // returns true.
-func (t *Tree[T]) Replace(key T) bool {
-	ins, ok, _, _ := t.insert(key, true, t.root, t.limit(t.size+1))
+func (t *Tree[T]) Replace(key T) bool { return t.upsert(key, replaceKey) }
+
+// upsert inserts key into the tree with the given update function (see
+// insert), and reports whether a new node was added.
+func (t *Tree[T]) upsert(key T, update func(*node[T], T, bool)) bool {
+	ok := t.insert(key, update)
 	t.incSize(ok)
-	t.root = ins
 	return ok
 }
 
+// replaceKey is an update function for insert that replaces the key of an
+// existing node with an equivalent key.
+func replaceKey[T any](n *node[T], key T, added bool) {
+	if !added {
+		n.X = key
+	}
+}
+
+// GetOrAdd returns the key in the tree equivalent to key, if there is one.
+// Otherwise, it adds the key returned by calling newKey, and returns that
+// key. It reports whether a new key was added. The key returned by newKey
+// must be equivalent to key.
+//
+// GetOrAdd searches the tree only once, so it is more efficient than calling
+// Get followed by Add. The newKey function is called only if key is not
+// already present. This is useful to construct a value on demand in a tree
+// of key-value pairs.
+func (t *Tree[T]) GetOrAdd(key T, newKey func() T) (_ T, added bool) {
+	var out T
+	ok := t.upsert(key, func(n *node[T], _ T, added bool) {
+		if added {
+			n.X = newKey()
+		}
+		out = n.X
+	})
+	return out, ok
+}
+
+// ReplaceAll inserts all the keys of seq into the tree as if by Replace, and
+// reports the number of keys that were newly added. If seq contains several
+// equivalent keys, the last one is retained.
+//
+// Rather than rebalancing after each insertion, ReplaceAll rebuilds the tree
+// once after all the keys have been merged. For a tree of n elements and k new
+// keys this takes O(n + k lg k) time, which is much faster than k separate
+// calls to Replace when k is large.
+func (t *Tree[T]) ReplaceAll(seq iter.Seq[T]) int {
+	var add []*node[T]
+	for key := range seq {
+		add = append(add, t.newNode(key))
+	}
+	t.sortNodes(add)
+	return t.mergeNodes(add, true)
+}
+
+// AddAll inserts all the given keys into the tree as if by Add, and reports
+// the number of keys that were newly added. Keys already present in the tree
+// are not modified, and if keys contains several equivalent keys, the first
+// one is retained.
+//
+// Like ReplaceAll, AddAll rebuilds the tree once after all the keys have been
+// merged, taking O(n + k lg k) time for a tree of n elements and k new keys.
+func (t *Tree[T]) AddAll(keys ...T) int {
+	add := make([]*node[T], len(keys))
+	for i, key := range keys {
+		add[i] = t.newNode(key)
+	}
+	t.sortNodes(add)
+	return t.mergeNodes(add, false)
+}
+
+// Merge adds all the keys of other to t as if by Add, and reports the number
+// of keys that were newly added. Keys already present in t are not modified.
+// The trees must use equivalent comparison functions. Merge does not modify
+// other.
+//
+// Because the keys of both trees are already in order, Merge takes O(n + m)
+// time for trees of n and m elements, rather than the O(m lg n) time needed to
+// add the keys of other one at a time.
+func (t *Tree[T]) Merge(other *Tree[T]) int {
+	add := make([]*node[T], 0, other.size)
+	for key := range other.Inorder {
+		add = append(add, t.newNode(key))
+	}
+	return t.mergeNodes(add, false)
+}
+
+// sortNodes sorts nodes in order by their keys, preserving the relative order
+// of equivalent keys.
+func (t *Tree[T]) sortNodes(nodes []*node[T]) {
+	slices.SortStableFunc(nodes, func(a, b *node[T]) int {
+		return t.compare(a.X, b.X)
+	})
+}
+
+// mergeNodes merges the nodes of add, which must be sorted, into t in order,
+// and rebuilds the tree.
+// If replace is true, the keys of existing nodes are updated by the last
+// equivalent new key; otherwise the existing key or the first equivalent new
+// key is retained. It returns the number of nodes added to the tree.
+func (t *Tree[T]) mergeNodes(add []*node[T], replace bool) int {
+	if len(add) == 0 {
+		return 0
+	}
+
+	// Merge the new nodes with the existing ones in order.
+	nodes := make([]*node[T], 0, t.size+len(add))
+	old := treeToVine(t.root)
+	for old != nil || len(add) != 0 {
+		var next *node[T]
+		if len(add) == 0 {
+			next, old = old, old.right
+		} else if old == nil {
+			next, add = add[0], add[1:]
+		} else if c := t.compare(old.X, add[0].X); c < 0 {
+			next, old = old, old.right
+		} else if c > 0 {
+			next, add = add[0], add[1:]
+		} else {
+			if replace {
+				old.X = add[0].X
+			}
+			add = add[1:]
+			continue // old may be matched again by a following key
+		}
+		if n := len(nodes); n != 0 && t.compare(nodes[n-1].X, next.X) == 0 {
+			if replace {
+				nodes[n-1].X = next.X // duplicate among the new keys
+			}
+			continue
+		}
+		nodes = append(nodes, next)
+	}
+	added := len(nodes) - t.size
+	t.root = extract(nodes)
+	t.fixAll(t.root)
+	t.size = len(nodes)
+	t.max = t.size
+	t.mods++
+	return added
+}
+
+// RemoveAll removes all the given keys from the tree, and reports the number
+// of keys that were removed. Keys not present in the tree are ignored.
+//
+// Rather than rebalancing after each removal, RemoveAll rebuilds the tree once
+// after all the keys have been removed. For a tree of n elements and k keys
+// this takes O(n + k lg k) time, which is much faster than k separate calls to
+// Remove when k is large.
+func (t *Tree[T]) RemoveAll(keys ...T) int {
+	if len(keys) == 0 || t.root == nil {
+		return 0
+	}
+	del := slices.Clone(keys)
+	slices.SortFunc(del, t.compare)
+
+	// The keys of the tree are visited in order, so the sorted keys to delete
+	// can be consumed as we go.
+	return t.removeIf(func(key T) bool {
+		for len(del) != 0 && t.compare(del[0], key) < 0 {
+			del = del[1:]
+		}
+		return len(del) != 0 && t.compare(del[0], key) == 0
+	})
+}
+
+// RemoveIf removes all the keys from the tree for which pred returns true,
+// and reports the number of keys that were removed. The predicate is called
+// once for each key in order, and must not modify the tree.
+//
+// Like RemoveAll, RemoveIf rebuilds the tree once after all the keys have been
+// removed, rather than rebalancing after each removal. For a tree of n
+// elements this takes O(n) time.
+func (t *Tree[T]) RemoveIf(pred func(key T) bool) int {
+	if t.root == nil {
+		return 0
+	}
+	return t.removeIf(pred)
+}
+
+// removeIf removes the keys of the non-empty tree for which pred returns true,
+// rebuilds the tree, and reports the number of keys removed.
+func (t *Tree[T]) removeIf(pred func(T) bool) int {
+	// Unlink the nodes for the deleted keys from the vine in place.
+	stub := &node[T]{right: treeToVine(t.root)}
+	size := 0
+	for prev := stub; prev.right != nil; {
+		cur := prev.right
+		if pred(cur.X) {
+			prev.right = cur.right
+			cur.right = nil
+		} else {
+			prev = cur
+			size++
+		}
+	}
+	removed := t.size - size
+	t.root = vineToTree(stub.right, size)
+	t.fixAll(t.root)
+	t.size = size
+	t.max = size
+	t.mods++
+	return removed
+}
+
+// Split removes all the keys greater than or equal to key from t, and returns
+// a new tree containing the removed keys, with the same settings as t. After
+// Split, t contains only the keys less than key. Either tree may be empty.
+//
+// Split takes O(n) time for a tree of n elements, and does not allocate
+// storage apart from the new tree. Any cursors into t whose keys were moved to
+// the new tree become invalid.
+func (t *Tree[T]) Split(key T) *Tree[T] {
+	hi := &Tree[T]{β: t.β, compare: t.compare, limit: t.limit, aug: t.aug}
+
+	// Find the last node of the vine less than key, and cut the vine after it.
+	stub := &node[T]{right: treeToVine(t.root)}
+	last, size := stub, 0
+	for last.right != nil && t.compare(last.right.X, key) < 0 {
+		last = last.right
+		size++
+	}
+	rest := last.right
+	last.right = nil
+
+	hi.size = t.size - size
+	hi.max = hi.size
+	hi.root = vineToTree(rest, hi.size)
+	hi.fixAll(hi.root)
+
+	t.size = size
+	t.max = size
+	t.root = vineToTree(stub.right, size)
+	t.fixAll(t.root)
+	t.mods++
+	return hi
+}
+
 // incSize increments t.size and updates t.max if inserted is true.
 func (t *Tree[T]) incSize(inserted bool) {
 	if inserted {
 		t.size++
+		t.mods++
 		if t.size > t.max {
 			t.max = t.size
 		}
 	}
 }
 
-// insert key in order under root, with the given depth limit.
-//
-// If replace is true and an existing node has an equivalent key, it is updated
-// with the given key; otherwise, inserting an existing key is a no-op.
+// insert adds key to the tree in order, and reports whether a new node was
+// added.
 //
-// Returns the modified tree, and reports whether a new node was added and the
-// height of the returned node above the point of insertion.
-// If the insertion did not exceed the depth limit, size == 0.
-// Otherwise, size == ins.size() meaning a scapegoat is needed.
-func (t *Tree[T]) insert(key T, replace bool, root *node[T], limit int) (ins *node[T], added bool, size, height int) {
-	// Descending phase: Insert the key into the tree structure.
-	var sib *node[T]
-	if root == nil {
-		// This is the base case where we add mass to the tree.  If we exceeded
-		// the depth limit reaching this point, flag it to the caller by
-		// returning a non-zero size. The 1 accounts for the node we just
-		// inserted; the caller will update it as we unwind the insertion.
-		if limit < 0 {
-			size = 1
+// If update != nil, it is called with the node for key, the key, and whether
+// that node was newly added, before any rebalancing; update may change the key
+// of the node to an equivalent value. If update == nil, inserting an existing
+// key is a no-op.
+func (t *Tree[T]) insert(key T, update func(*node[T], T, bool)) bool {
+	// Descending phase: Find where key belongs, recording the path from the
+	// root. The buffer is large enough for most trees without allocating.
+	var buf [64]*node[T]
+	path := buf[:0]
+	link := &t.root
+	for cur := t.root; cur != nil; cur = *link {
+		cmp := t.compare(key, cur.X)
+		if cmp == 0 {
+			// Replacing an existing node. This cannot introduce a violation, so
+			// we can return immediately without triggering a goat search.
+			if update != nil {
+				update(cur, key, false)
+				if t.aug != nil {
+					t.fix(cur)
+					t.fixPath(path)
+				}
+			}
+			return false
 		}
-		return &node[T]{X: key}, true, size, 0
-	}
-	cmp := t.compare(key, root.X)
-	if cmp < 0 {
-		ins, added, size, height = t.insert(key, replace, root.left, limit-1)
-		root.left = ins
-		sib = root.right
-		height++
-	} else if cmp > 0 {
-		ins, added, size, height = t.insert(key, replace, root.right, limit-1)
-		root.right = ins
-		sib = root.left
-		height++
-	} else {
-		// Replacing an existing node. This cannot introduce a violation, so we
-		// can return immediately without triggering a goat search.
-		if replace {
-			root.X = key
+		path = append(path, cur)
+		if cmp < 0 {
+			link = &cur.left
+		} else {
+			link = &cur.right
 		}
-		return root, false, 0, 0
+	}
+
+	// This is where we add mass to the tree.
+	n := t.newNode(key)
+	if update != nil {
+		update(n, key, true)
+	}
+	*link = n
+	for _, p := range path {
+		p.n++
+	}
+	if t.aug != nil {
+		t.fix(n)
+		t.fixPath(path)
 	}
 
 	// Ascending phase, a.k.a., goat rodeo.
 	// Uses the selection strategy from section 4.6 of Galperin & Rivest.
-
-	// If size != 0, we exceeded the depth limit and are looking for a goat.
-	// Note: size == ins.size() not root.size() at this point.
-	if size > 0 {
-		sibSize := sib.size()          // size of sibling subtree
-		rootSize := sibSize + 1 + size // new size of root
-
-		if bw := t.limit(rootSize); height <= bw {
-			// Update the size to include the rest of the current root, but this
-			// is not the scapegoat yet. Keep unwinding.
-			size = rootSize
-		} else {
-			// root is the goat; rewrite it and signal the activations above us
-			// to stop looking by setting size to 0.
-			root = rewrite(root, rootSize)
-			size = 0
+	//
+	// If the new node exceeds the depth limit, walk back up the path to find
+	// the lowest ancestor whose height exceeds the limit for its size, and
+	// rewrite its subtree. If there is no such ancestor, no goat is needed.
+	if len(path) > t.limit(t.size+1) {
+		for i := len(path) - 1; i >= 0; i-- {
+			root := path[i]
+			if height := len(path) - i; height <= t.limit(root.n) {
+				continue // not the goat; keep unwinding
+			}
+			goat := t.rewrite(root, root.n)
+			if i == 0 {
+				t.root = goat
+			} else if par := path[i-1]; par.left == root {
+				par.left = goat
+			} else {
+				par.right = goat
+			}
+			break
 		}
 	}
-	return root, added, size, height
+	return true
 }
 
 // Remove key from the tree and report whether it was present.
 func (t *Tree[T]) Remove(key T) bool {
-	del, ok := t.root.remove(key, t.compare)
-	t.root = del
+	var ok bool
+	if t.aug != nil {
+		ok = t.removeAugmented(key)
+	} else {
+		t.root, ok = t.root.remove(key, t.compare)
+	}
 	if ok {
-		t.size--
-		if bw := (t.max*t.β + maxBalance) / fracLimit; t.size < bw {
-			t.root = rewrite(t.root, t.size)
-			t.max = t.size
-		}
+		t.decSize()
 	}
 	return ok
 }
 
+// decSize decrements t.size after a node has been removed, and rebuilds the
+// tree if it has become too unbalanced. It reports whether the tree was
+// rebuilt.
+func (t *Tree[T]) decSize() bool {
+	t.size--
+	t.mods++
+	if bw := (t.max*t.β + maxBalance) / fracLimit; t.size < bw {
+		t.root = t.rewrite(t.root, t.size)
+		t.max = t.size
+		return true
+	}
+	return false
+}
+
 // remove key from the subtree under n, returning the modified tree reporting
 // whether the mass of the tree was decreased.
 func (n *node[T]) remove(key T, compare func(a, b T) int) (_ *node[T], ok bool) {
@@ -229,9 +552,15 @@ func (n *node[T]) remove(key T, compare func(a, b T) int) (_ *node[T], ok bool)
 	cmp := compare(key, n.X)
 	if cmp < 0 {
 		n.left, ok = n.left.remove(key, compare)
+		if ok {
+			n.n--
+		}
 		return n, ok
 	} else if cmp > 0 {
 		n.right, ok = n.right.remove(key, compare)
+		if ok {
+			n.n--
+		}
 		return n, ok
 	} else if n.left == nil {
 		return n.right, true
@@ -243,6 +572,7 @@ func (n *node[T]) remove(key T, compare func(a, b T) int) (_ *node[T], ok bool)
 	// Do the usual trick.
 	goat := popMinRight(n)
 	n.X = goat.X
+	n.n--
 	return n, true
 }
 
@@ -257,24 +587,168 @@ func (t *Tree[T]) Len() int { return t.size }
 // IsEmpty reports whether t is empty.
 func (t *Tree[T]) IsEmpty() bool { return t.size == 0 }
 
-// Clear discards all the values in t, leaving it empty.
-func (t *Tree[T]) Clear() { t.size = 0; t.max = 0; t.root = nil }
+// Height reports the number of keys on a longest path from the root of t to
+// a leaf. An empty tree has height 0. This operation takes time proportional
+// to the size of the tree.
+func (t *Tree[T]) Height() int { return t.root.height() }
+
+// Depths returns a histogram of the depths of the keys in t, in which the ith
+// element is the number of keys at depth i, with the root at depth 0. The
+// length of the result is t.Height(). If t is empty, Depths returns nil.
+// This operation takes time proportional to the size of the tree.
+//
+// The histogram is useful to assess how the balancing factor affects the
+// shape of the tree: A perfectly balanced tree has 2^i keys at each depth i
+// except the last.
+func (t *Tree[T]) Depths() []int { return t.root.depths(0, nil) }
+
+// Stats records statistics about the shape of a tree. See [Tree.Stats].
+type Stats struct {
+	Balance   int     // the balancing factor β of the tree
+	Len       int     // the number of keys in the tree
+	Height    int     // the number of keys on a longest path from the root
+	MinHeight int     // the least possible height for a tree of Len keys
+	MeanDepth float64 // the average depth of a key, with the root at depth 0
+	Depths    []int   // the number of keys at each depth, as for Tree.Depths
+}
+
+// Stats returns statistics about the shape of t, which are useful to measure
+// the effect of the balancing factor on a particular workload. This operation
+// takes time proportional to the size of the tree.
+func (t *Tree[T]) Stats() Stats {
+	s := Stats{
+		Balance:   t.β,
+		Len:       t.size,
+		MinHeight: bits.Len(uint(t.size)),
+		Depths:    t.Depths(),
+	}
+	s.Height = len(s.Depths)
+	if t.size != 0 {
+		var sum int
+		for d, n := range s.Depths {
+			sum += d * n
+		}
+		s.MeanDepth = float64(sum) / float64(t.size)
+	}
+	return s
+}
+
+// Clear discards all the values in t, leaving it empty. Any nodes retained by
+// a previous call to ClearReuse are also discarded.
+func (t *Tree[T]) Clear() {
+	t.size = 0
+	t.max = 0
+	t.root = nil
+	t.free = nil
+	t.mods++
+}
+
+// ClearReuse discards all the values in t, leaving it empty, but retains the
+// storage for its nodes to be reused by subsequent insertions. This reduces
+// allocation for a tree that is repeatedly filled and cleared.
+//
+// Unlike Clear, ClearReuse takes time proportional to the size of the tree,
+// and the retained storage is not released until the nodes are reused or
+// Clear is called. Any cursors into t are invalid after ClearReuse.
+func (t *Tree[T]) ClearReuse() {
+	var zero T
+	vine := treeToVine(t.root)
+	for cur := vine; cur != nil; cur = cur.right {
+		cur.X = zero // release the key for collection
+		if cur.right == nil {
+			cur.right = t.free
+			break
+		}
+	}
+	if vine != nil {
+		t.free = vine
+	}
+	t.size = 0
+	t.max = 0
+	t.root = nil
+	t.mods++
+}
+
+// newNode returns a node containing key, reusing a retained node if one is
+// available.
+func (t *Tree[T]) newNode(key T) *node[T] {
+	if n := t.free; n != nil {
+		t.free = n.right
+		n.X, n.right, n.n = key, nil, 1
+		return n
+	}
+	return &node[T]{X: key, n: 1}
+}
 
 // Get reports whether key is present in the tree, and returns the matching key
 // if so, or a zero value if the key is not present.
 func (t *Tree[T]) Get(key T) (_ T, ok bool) {
+	if n := t.lookup(key); n != nil {
+		return n.X, true
+	}
+	return
+}
+
+// lookup returns the node for key in t, or nil if key is not present.
+func (t *Tree[T]) lookup(key T) *node[T] {
+	cur := t.root
+	for cur != nil {
+		cmp := t.compare(key, cur.X)
+		if cmp < 0 {
+			cur = cur.left
+		} else if cmp > 0 {
+			cur = cur.right
+		} else {
+			return cur
+		}
+	}
+	return nil
+}
+
+// Floor reports whether t has a key less than or equal to key, and if so
+// returns the greatest such key. Otherwise it returns a zero key and false.
+// This operation takes O(lg n) time and does not allocate.
+func (t *Tree[T]) Floor(key T) (_ T, ok bool) {
+	var floor *node[T]
 	cur := t.root
 	for cur != nil {
 		cmp := t.compare(key, cur.X)
 		if cmp < 0 {
 			cur = cur.left
 		} else if cmp > 0 {
+			floor = cur
 			cur = cur.right
 		} else {
 			return cur.X, true
 		}
 	}
-	return
+	if floor == nil {
+		return
+	}
+	return floor.X, true
+}
+
+// Ceiling reports whether t has a key greater than or equal to key, and if so
+// returns the least such key. Otherwise it returns a zero key and false.
+// This operation takes O(lg n) time and does not allocate.
+func (t *Tree[T]) Ceiling(key T) (_ T, ok bool) {
+	var ceil *node[T]
+	cur := t.root
+	for cur != nil {
+		cmp := t.compare(key, cur.X)
+		if cmp < 0 {
+			ceil = cur
+			cur = cur.left
+		} else if cmp > 0 {
+			cur = cur.right
+		} else {
+			return cur.X, true
+		}
+	}
+	if ceil == nil {
+		return
+	}
+	return ceil.X, true
 }
 
 // Find returns a cursor to the smallest key in the tree greater than or equal
@@ -293,13 +767,141 @@ func (t *Tree[T]) Find(key T) *Cursor[T] {
 }
 
 // Inorder is a range function that visits each key of t in order.
-func (t *Tree[T]) Inorder(yield func(key T) bool) { t.root.inorder(yield) }
+func (t *Tree[T]) Inorder(yield func(key T) bool) { t.root.inorder(t.guard(yield)) }
+
+// guard wraps yield so that it panics if t is structurally modified during a
+// call to yield that does not end the iteration.
+func (t *Tree[T]) guard(yield func(T) bool) func(T) bool {
+	mods := t.mods
+	return func(key T) bool {
+		ok := yield(key)
+		if ok && t.mods != mods {
+			panic("stree: tree modified during iteration")
+		}
+		return ok
+	}
+}
 
 // InorderAfter returns a range function for each key greater than or equal to
 // key, in order.
 func (t *Tree[T]) InorderAfter(key T) iter.Seq[T] {
 	return func(yield func(T) bool) {
-		t.root.inorderAfter(key, t.compare, yield)
+		t.root.inorderAfter(key, t.compare, t.guard(yield))
+	}
+}
+
+// Between returns a range function for each key greater than or equal to lo
+// and less than hi, in order. If lo ≥ hi, the range is empty. Only the keys
+// in the range and those on the paths to its ends are visited, so this takes
+// O(lg n + k) time for a range of k keys.
+func (t *Tree[T]) Between(lo, hi T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		t.root.between(lo, hi, t.compare, t.guard(yield))
+	}
+}
+
+// ReverseInorder is a range function that visits each key of t in reverse
+// order, from greatest to least.
+func (t *Tree[T]) ReverseInorder(yield func(key T) bool) {
+	t.root.reverseInorder(t.guard(yield))
+}
+
+// InorderBefore returns a range function for each key less than or equal to
+// key, in reverse order.
+func (t *Tree[T]) InorderBefore(key T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		t.root.inorderBefore(key, t.compare, t.guard(yield))
+	}
+}
+
+// A Mark records the progress of a resumable inorder traversal of a tree.
+// The zero value is ready for use, and denotes the start of the traversal.
+// See [Tree.Resume].
+type Mark[T any] struct {
+	last T    // the most recent key delivered, if started
+	ok   bool // whether last is valid
+	done bool // whether the traversal has reached the end
+}
+
+// Key reports the most recent key delivered by a traversal using m, and
+// whether any key has been delivered.
+func (m *Mark[T]) Key() (T, bool) { return m.last, m.ok }
+
+// Done reports whether the traversal using m has visited all the keys of the
+// tree. Once done, a traversal using m visits no further keys, even if keys
+// are subsequently added to the tree.
+func (m *Mark[T]) Done() bool { return m.done }
+
+// Resume returns a range function that visits each key of t in order,
+// beginning immediately after the most recent key recorded by m.  As each key
+// is delivered, m is updated to record it, so that a later call to Resume with
+// the same mark continues where this one stopped.
+//
+// Because the mark records a key rather than a location in the tree
+// structure, t may be modified between calls to Resume. Keys added before the
+// mark are not visited, and removing the key recorded by the mark does not
+// affect the traversal. The tree must not be structurally modified while the
+// range function is running.
+func (t *Tree[T]) Resume(m *Mark[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if m.done {
+			return
+		}
+		visit := t.guard(func(key T) bool {
+			m.last, m.ok = key, true
+			return yield(key)
+		})
+		if !m.ok {
+			m.done = t.root.inorder(visit)
+			return
+		}
+		last := m.last
+		m.done = t.root.inorderAfter(last, t.compare, func(key T) bool {
+			if t.compare(key, last) == 0 {
+				return true // already delivered
+			}
+			return visit(key)
+		})
+	}
+}
+
+// Rank reports the number of keys in t that are less than key, whether or not
+// key itself is present. This operation takes O(lg n) time.
+func (t *Tree[T]) Rank(key T) int {
+	var rank int
+	cur := t.root
+	for cur != nil {
+		cmp := t.compare(key, cur.X)
+		if cmp <= 0 {
+			if cmp == 0 {
+				return rank + cur.left.size()
+			}
+			cur = cur.left
+		} else {
+			rank += cur.left.size() + 1
+			cur = cur.right
+		}
+	}
+	return rank
+}
+
+// Select reports whether t has a key at offset i in order, and if so returns
+// that key, so that Select(0) is the minimum key. If i < 0 or i ≥ t.Len(),
+// Select returns a zero key and false. This operation takes O(lg n) time.
+func (t *Tree[T]) Select(i int) (_ T, ok bool) {
+	if i < 0 || i >= t.size {
+		return
+	}
+	cur := t.root
+	for {
+		if n := cur.left.size(); i < n {
+			cur = cur.left
+		} else if i > n {
+			i -= n + 1
+			cur = cur.right
+		} else {
+			return cur.X, true
+		}
 	}
 }
 
@@ -310,7 +912,7 @@ func (t *Tree[T]) Cursor(key T) *Cursor[T] {
 	if len(path) == 0 || t.compare(path[len(path)-1].X, key) != 0 {
 		return nil
 	}
-	return &Cursor[T]{path: path}
+	return &Cursor[T]{tree: t, path: path, mods: t.mods}
 }
 
 // Root returns a Cursor to the root of t, or nil if t is empty.
@@ -318,10 +920,11 @@ func (t *Tree[T]) Root() *Cursor[T] {
 	if t.root == nil {
 		return nil
 	}
-	return &Cursor[T]{path: []*node[T]{t.root}}
+	return &Cursor[T]{tree: t, path: []*node[T]{t.root}, mods: t.mods}
 }
 
 // Min returns the minimum key in t. If t is empty, a zero key is returned.
+// Use MinOK to distinguish an empty tree.
 func (t *Tree[T]) Min() T {
 	cur := t.root
 	if cur == nil {
@@ -335,6 +938,7 @@ func (t *Tree[T]) Min() T {
 }
 
 // Max returns the maximum key in t. If t is empty, a zero key is returned.
+// Use MaxOK to distinguish an empty tree.
 func (t *Tree[T]) Max() T {
 	cur := t.root
 	if cur == nil {
@@ -347,6 +951,75 @@ func (t *Tree[T]) Max() T {
 	return cur.X
 }
 
+// MinOK reports whether t is non-empty, and if so returns its minimum key.
+func (t *Tree[T]) MinOK() (T, bool) {
+	if t.root == nil {
+		var zero T
+		return zero, false
+	}
+	return t.Min(), true
+}
+
+// MaxOK reports whether t is non-empty, and if so returns its maximum key.
+func (t *Tree[T]) MaxOK() (T, bool) {
+	if t.root == nil {
+		var zero T
+		return zero, false
+	}
+	return t.Max(), true
+}
+
+// PopMin reports whether t is non-empty, and if so removes and returns its
+// minimum key. This is more efficient than calling Min followed by Remove,
+// since it descends the tree only once and does no comparisons.
+func (t *Tree[T]) PopMin() (T, bool) {
+	if t.root == nil {
+		var zero T
+		return zero, false
+	}
+	var parent *node[T]
+	cur := t.root
+	for cur.left != nil {
+		cur.n-- // cur will lose a descendant
+		parent, cur = cur, cur.left
+	}
+	if parent == nil {
+		t.root = cur.right
+	} else {
+		parent.left = cur.right
+	}
+	if t.aug != nil {
+		t.fixSpine(t.root, true)
+	}
+	t.decSize()
+	return cur.X, true
+}
+
+// PopMax reports whether t is non-empty, and if so removes and returns its
+// maximum key. Like PopMin, it descends the tree only once.
+func (t *Tree[T]) PopMax() (T, bool) {
+	if t.root == nil {
+		var zero T
+		return zero, false
+	}
+	var parent *node[T]
+	cur := t.root
+	for cur.right != nil {
+		cur.n-- // cur will lose a descendant
+		parent, cur = cur, cur.right
+	}
+	if parent == nil {
+		t.root = cur.left
+	} else {
+		parent.right = cur.left
+	}
+	if t.aug != nil {
+		t.fixSpine(t.root, false)
+	}
+	t.decSize()
+	return cur.X, true
+}
+
 // KV is a convenience type for storing key-value pairs in a Tree, where the
 // key type T is used for comparison while the value type U is ignored.  Use
 // the Compare method to adapt a comparison for T to a KV on T.
@@ -1,16 +1,25 @@
 package stree_test
 
 import (
+	"bytes"
 	"cmp"
+	"encoding/gob"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
+	"iter"
+	"math/bits"
 	"os"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 
+	"github.com/creachadair/mds/internal/mdtest"
 	"github.com/creachadair/mds/mapset"
+	"github.com/creachadair/mds/mtest"
 	"github.com/creachadair/mds/stree"
 	gocmp "github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -75,6 +84,40 @@ func TestNew(t *testing.T) {
 	})
 }
 
+func TestNewSorted(t *testing.T) {
+	if tree := stree.NewSorted(100, cmp.Compare[int]); !tree.IsEmpty() {
+		t.Errorf("NewSorted empty: got %v, want empty", tree)
+	}
+
+	const numKeys = 1000
+	keys := make([]int, numKeys)
+	for i := range keys {
+		keys[i] = 3 * i
+	}
+	tree := stree.NewSorted(250, cmp.Compare[int], keys...)
+	if diff := gocmp.Diff(slices.Collect(tree.Inorder), keys); diff != "" {
+		t.Errorf("NewSorted (-got, +want):\n%s", diff)
+	}
+	if got, want := tree.Height(), bits.Len(numKeys); got != want {
+		t.Errorf("Height: got %d, want %d", got, want)
+	}
+	if got, ok := tree.Select(500); !ok || got != 1500 {
+		t.Errorf("Select(500): got (%d, %v), want (1500, true)", got, ok)
+	}
+
+	// The tree is usable after construction.
+	tree.Add(1)
+	tree.Remove(3)
+	if got := tree.Rank(6); got != 2 {
+		t.Errorf("Rank(6): got %d, want 2", got)
+	}
+
+	// Keys out of order or with duplicates are rejected.
+	mtest.MustPanic(t, func() { stree.NewSorted(250, cmp.Compare[int], 1, 3, 2) })
+	mtest.MustPanic(t, func() { stree.NewSorted(250, cmp.Compare[int], 1, 2, 2, 3) })
+	mtest.MustPanic(t, func() { stree.NewSorted(-1, cmp.Compare[int]) })
+}
+
 func TestRemoval(t *testing.T) {
 	words := strings.Fields(`a foolish consistency is the hobgoblin of little minds`)
 	tree := stree.New(0, cmp.Compare, words...)
@@ -162,6 +205,266 @@ func TestInorderAfter(t *testing.T) {
 	}
 }
 
+func TestBetween(t *testing.T) {
+	keys := []string{"8", "6", "7", "5", "3", "0", "9"}
+	tree := stree.New(0, cmp.Compare[string], keys...)
+	tests := []struct {
+		lo, hi string
+		want   string
+	}{
+		{"", "A", "0 3 5 6 7 8 9"},
+		{"0", "9", "0 3 5 6 7 8"},
+		{"1", "5", "3"},
+		{"3", "6", "3 5"},
+		{"4", "7", "5 6"},
+		{"7", "A", "7 8 9"},
+		{"5", "5", ""},
+		{"6", "5", ""},
+		{"A", "B", ""},
+		{"", "0", ""},
+	}
+	for _, test := range tests {
+		want := strings.Fields(test.want)
+		var got []string
+		for key := range tree.Between(test.lo, test.hi) {
+			got = append(got, key)
+		}
+		if diff := gocmp.Diff(want, got, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("Between(%q, %q) result differed from expected\n%s", test.lo, test.hi, diff)
+		}
+	}
+
+	t.Run("Stop", func(t *testing.T) {
+		var visited []string
+		tree := stree.New(0, func(a, b string) int {
+			visited = append(visited, a) // the tree key
+			return cmp.Compare(a, b)
+		}, keys...)
+		visited = nil
+
+		var got []string
+		for key := range tree.Between("3", "9") {
+			got = append(got, key)
+			if len(got) == 2 {
+				break
+			}
+		}
+		if diff := gocmp.Diff(got, []string{"3", "5"}); diff != "" {
+			t.Errorf("Between (-got, +want):\n%s", diff)
+		}
+		if slices.Contains(visited, "8") || slices.Contains(visited, "9") {
+			t.Errorf("Between visited keys after stopping: %q", visited)
+		}
+	})
+}
+
+func TestMultiset(t *testing.T) {
+	// Compare keys without regard to case, so equivalent keys are not equal.
+	foldCompare := func(a, b string) int { return cmp.Compare(strings.ToLower(a), strings.ToLower(b)) }
+	all := func(seq iter.Seq[string]) []string { return slices.Collect(seq) }
+
+	m := stree.NewMultiset(100, foldCompare, "b", "a", "B", "c", "A", "b")
+	checkLen := func(t *testing.T, n, distinct int) {
+		t.Helper()
+		if got := m.Len(); got != n {
+			t.Errorf("Len: got %d, want %d", got, n)
+		}
+		if got := m.Distinct(); got != distinct {
+			t.Errorf("Distinct: got %d, want %d", got, distinct)
+		}
+		if got := m.IsEmpty(); got != (n == 0) {
+			t.Errorf("IsEmpty: got %v, want %v", got, n == 0)
+		}
+	}
+	checkKeys := func(t *testing.T, want ...string) {
+		t.Helper()
+		if diff := gocmp.Diff(all(m.Inorder), want, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("Keys (-got, +want):\n%s", diff)
+		}
+	}
+
+	checkLen(t, 6, 3)
+	checkKeys(t, "a", "A", "b", "B", "b", "c")
+	for _, tc := range []struct {
+		key  string
+		want int
+	}{{"a", 2}, {"B", 3}, {"c", 1}, {"d", 0}} {
+		if got := m.CountOf(tc.key); got != tc.want {
+			t.Errorf("CountOf(%q): got %d, want %d", tc.key, got, tc.want)
+		}
+	}
+	if diff := gocmp.Diff(all(m.Between("b", "d")), []string{"b", "B", "b", "c"}); diff != "" {
+		t.Errorf("Between (-got, +want):\n%s", diff)
+	}
+
+	t.Run("Add", func(t *testing.T) {
+		if got := m.Add("C"); got != 2 {
+			t.Errorf("Add(C): got %d, want 2", got)
+		}
+		if got := m.Add("d"); got != 1 {
+			t.Errorf("Add(d): got %d, want 1", got)
+		}
+		checkLen(t, 8, 4)
+		checkKeys(t, "a", "A", "b", "B", "b", "c", "C", "d")
+	})
+
+	t.Run("RemoveOne", func(t *testing.T) {
+		if !m.RemoveOne("B") {
+			t.Error("RemoveOne(B): got false, want true")
+		}
+		if !m.RemoveOne("d") {
+			t.Error("RemoveOne(d): got false, want true")
+		}
+		if m.RemoveOne("e") {
+			t.Error("RemoveOne(e): got true, want false")
+		}
+		checkLen(t, 6, 3)
+		checkKeys(t, "a", "A", "b", "B", "c", "C")
+	})
+
+	t.Run("RemoveAll", func(t *testing.T) {
+		if got := m.RemoveAll("A"); got != 2 {
+			t.Errorf("RemoveAll(A): got %d, want 2", got)
+		}
+		if got := m.RemoveAll("a"); got != 0 {
+			t.Errorf("RemoveAll(a): got %d, want 0", got)
+		}
+		checkLen(t, 4, 2)
+		checkKeys(t, "b", "B", "c", "C")
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		m.Clear()
+		checkLen(t, 0, 0)
+		checkKeys(t)
+	})
+
+	t.Run("Many", func(t *testing.T) {
+		// Add and remove enough keys to force rebalancing.
+		m := stree.NewMultiset(0, cmp.Compare[int])
+		for i := range 1000 {
+			m.Add(i % 37)
+		}
+		for i := range 37 {
+			want := 1000 / 37
+			if i < 1000%37 {
+				want++
+			}
+			if got := m.CountOf(i); got != want {
+				t.Errorf("CountOf(%d): got %d, want %d", i, got, want)
+			}
+		}
+		for i := range 30 {
+			m.RemoveAll(i)
+		}
+		if got := slices.Collect(m.Inorder); !slices.IsSorted(got) || len(got) != m.Len() {
+			t.Errorf("Inorder: got %d keys, want %d in order", len(got), m.Len())
+		}
+		if got, want := m.Distinct(), 7; got != want {
+			t.Errorf("Distinct: got %d, want %d", got, want)
+		}
+	})
+}
+
+func TestInorderBefore(t *testing.T) {
+	keys := []string{"8", "6", "7", "5", "3", "0", "9"}
+	tree := stree.New(0, cmp.Compare[string], keys...)
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"", ""},
+		{"0", "0"},
+		{"1", "0"},
+		{"3", "3 0"},
+		{"4", "3 0"},
+		{"5", "5 3 0"},
+		{"6", "6 5 3 0"},
+		{"8", "8 7 6 5 3 0"},
+		{"9", "9 8 7 6 5 3 0"},
+		{"A", "9 8 7 6 5 3 0"},
+	}
+	for _, test := range tests {
+		want := strings.Fields(test.want)
+		var got []string
+		for key := range tree.InorderBefore(test.key) {
+			got = append(got, key)
+		}
+		if diff := gocmp.Diff(want, got, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("InorderBefore(%v) result differed from expected\n%s", test.key, diff)
+		}
+	}
+
+	got := slices.Collect(tree.ReverseInorder)
+	if diff := gocmp.Diff(got, strings.Fields("9 8 7 6 5 3 0")); diff != "" {
+		t.Errorf("ReverseInorder (-got, +want):\n%s", diff)
+	}
+
+	var some []string
+	for key := range tree.ReverseInorder {
+		some = append(some, key)
+		if len(some) == 3 {
+			break
+		}
+	}
+	if diff := gocmp.Diff(some, strings.Fields("9 8 7")); diff != "" {
+		t.Errorf("ReverseInorder with break (-got, +want):\n%s", diff)
+	}
+}
+
+func TestResume(t *testing.T) {
+	tree := stree.New(100, cmp.Compare[string], strings.Fields("a c e g i k m")...)
+
+	// Scan up to n keys using m.
+	var m stree.Mark[string]
+	scan := func(n int) []string {
+		var got []string
+		for key := range tree.Resume(&m) {
+			got = append(got, key)
+			if len(got) == n {
+				break
+			}
+		}
+		return got
+	}
+	check := func(n int, want string) {
+		t.Helper()
+		got := scan(n)
+		if diff := gocmp.Diff(got, strings.Fields(want), cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("Resume %d (-got, +want):\n%s", n, diff)
+		}
+	}
+
+	if key, ok := m.Key(); ok {
+		t.Errorf("Initial mark: got %q, want none", key)
+	}
+	check(2, "a c")
+	if key, ok := m.Key(); !ok || key != "c" {
+		t.Errorf("Mark: got %q, %v; want c, true", key, ok)
+	}
+
+	// Keys added before the mark are not visited, keys after it are.
+	tree.Add("b")
+	tree.Add("d")
+	check(2, "d e")
+
+	// Removing the marked key does not disturb the traversal.
+	tree.Remove("e")
+	tree.Remove("g")
+	check(1, "i")
+	if m.Done() {
+		t.Error("Mark should not be done yet")
+	}
+	check(10, "k m")
+	if !m.Done() {
+		t.Error("Mark should be done")
+	}
+
+	// A finished mark visits nothing further.
+	tree.Add("z")
+	check(10, "")
+}
+
 func TestCursor(t *testing.T) {
 	t.Run("EmptyTree", func(t *testing.T) {
 		tree := stree.New(250, strings.Compare)
@@ -369,6 +672,202 @@ func TestCursor(t *testing.T) {
 	})
 }
 
+func TestCursorEdit(t *testing.T) {
+	t.Run("Set", func(t *testing.T) {
+		type kv = stree.KV[string, int]
+		tree := stree.New(250, kv{}.Compare(cmp.Compare), kv{"a", 1}, kv{"b", 2}, kv{"c", 3})
+
+		c := tree.Cursor(kv{Key: "b"})
+		if !c.Set(kv{"b", 20}) {
+			t.Error("Set(b): got false, want true")
+		}
+		if c.Set(kv{"x", 0}) {
+			t.Error("Set(x): got true, want false")
+		}
+		if got := c.Key(); got != (kv{"b", 20}) {
+			t.Errorf("Key: got %v, want b=20", got)
+		}
+		if got, _ := tree.Get(kv{Key: "b"}); got.Value != 20 {
+			t.Errorf("Get(b): got %v, want b=20", got)
+		}
+		if c.Next().Next().Set(kv{"c", 30}) {
+			t.Error("Set on invalid cursor: got true, want false")
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		words := strings.Fields(strings.Repeat("the quick brown fox jumps over the lazy dog and cat ", 20))
+		for i := range words {
+			words[i] += fmt.Sprint(i % 17)
+		}
+		for _, β := range []int{0, 100, 500} {
+			tree := stree.New(β, cmp.Compare[string], words...)
+			want := sortedUnique(words, nil)
+
+			// Delete every key containing "o" while iterating forward.
+			var kept []string
+			c := tree.Root().Min()
+			for c.Valid() {
+				if key := c.Key(); strings.Contains(key, "o") {
+					c.Delete()
+				} else {
+					kept = append(kept, key)
+					c.Next()
+				}
+			}
+			var wantKept []string
+			for _, w := range want {
+				if !strings.Contains(w, "o") {
+					wantKept = append(wantKept, w)
+				}
+			}
+			if diff := gocmp.Diff(kept, wantKept); diff != "" {
+				t.Errorf("β=%d visited (-got, +want):\n%s", β, diff)
+			}
+			if diff := gocmp.Diff(allWords(tree), wantKept); diff != "" {
+				t.Errorf("β=%d after delete (-got, +want):\n%s", β, diff)
+			}
+			if tree.Len() != len(wantKept) {
+				t.Errorf("β=%d Len: got %d, want %d", β, tree.Len(), len(wantKept))
+			}
+
+			// Delete the rest from the root down, checking the successor.
+			for tree.Len() != 0 {
+				c := tree.Root()
+				key := c.Key()
+				var next string
+				for w := range tree.InorderAfter(key) {
+					if w != key {
+						next = w
+						break
+					}
+				}
+				if got := c.Delete().Key(); got != next {
+					t.Errorf("β=%d Delete(%q): got successor %q, want %q", β, key, got, next)
+				}
+				if _, ok := tree.Get(key); ok {
+					t.Errorf("β=%d Delete(%q): key is still present", β, key)
+				}
+			}
+		}
+
+		var nc *stree.Cursor[int]
+		if nc.Delete().Valid() {
+			t.Error("Delete on nil cursor: got valid, want invalid")
+		}
+	})
+}
+
+func TestModifyDuringIteration(t *testing.T) {
+	newTree := func() *stree.Tree[int] {
+		return stree.New(100, cmp.Compare[int], 1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+	}
+
+	t.Run("Panic", func(t *testing.T) {
+		tests := []struct {
+			name string
+			seq  func(*stree.Tree[int]) iter.Seq[int]
+		}{
+			{"Inorder", func(t *stree.Tree[int]) iter.Seq[int] { return t.Inorder }},
+			{"ReverseInorder", func(t *stree.Tree[int]) iter.Seq[int] { return t.ReverseInorder }},
+			{"InorderAfter", func(t *stree.Tree[int]) iter.Seq[int] { return t.InorderAfter(3) }},
+			{"InorderBefore", func(t *stree.Tree[int]) iter.Seq[int] { return t.InorderBefore(8) }},
+			{"Between", func(t *stree.Tree[int]) iter.Seq[int] { return t.Between(2, 9) }},
+			{"Resume", func(t *stree.Tree[int]) iter.Seq[int] { return t.Resume(new(stree.Mark[int])) }},
+			{"Cursor", func(t *stree.Tree[int]) iter.Seq[int] { return t.Root().Inorder }},
+		}
+		mods := []struct {
+			name string
+			edit func(*stree.Tree[int])
+		}{
+			{"Add", func(t *stree.Tree[int]) { t.Add(100) }},
+			{"Remove", func(t *stree.Tree[int]) { t.Remove(5) }},
+			{"PopMin", func(t *stree.Tree[int]) { t.PopMin() }},
+			{"Clear", func(t *stree.Tree[int]) { t.Clear() }},
+		}
+		for _, tc := range tests {
+			for _, m := range mods {
+				t.Run(tc.name+"/"+m.name, func(t *testing.T) {
+					tree := newTree()
+					v := mtest.MustPanic(t, func() {
+						for range tc.seq(tree) {
+							m.edit(tree)
+						}
+					})
+					if s, ok := v.(string); !ok || !strings.Contains(s, "modified during iteration") {
+						t.Errorf("Panic: got %v, want modified during iteration", v)
+					}
+				})
+			}
+		}
+	})
+
+	t.Run("NoPanic", func(t *testing.T) {
+		type kv = stree.KV[int, string]
+		tree := stree.New(100, kv{}.Compare(cmp.Compare), kv{1, "a"}, kv{2, "b"}, kv{3, "c"})
+
+		// Replacing a key with an equivalent one is not a structural change.
+		var got []string
+		for key := range tree.Inorder {
+			tree.Replace(kv{key.Key, key.Value + "!"})
+			got = append(got, key.Value)
+		}
+		if diff := gocmp.Diff(got, []string{"a", "b", "c"}); diff != "" {
+			t.Errorf("Replace (-got, +want):\n%s", diff)
+		}
+
+		// Modifying the tree is OK if the iteration stops.
+		for key := range tree.Inorder {
+			tree.Remove(key)
+			break
+		}
+		if got := tree.Len(); got != 2 {
+			t.Errorf("Len: got %d, want 2", got)
+		}
+	})
+
+	t.Run("Cursor", func(t *testing.T) {
+		tree := newTree()
+		c := tree.Cursor(4)
+		d := tree.Cursor(9)
+
+		// Adding enough keys to rebuild the tree does not disturb the cursors.
+		for i := 11; i <= 200; i++ {
+			tree.Add(i)
+		}
+		if got := c.Key(); got != 4 {
+			t.Errorf("Cursor after Add: got %d, want 4", got)
+		}
+		if got := c.Clone().Next().Key(); got != 5 {
+			t.Errorf("Next after Add: got %d, want 5", got)
+		}
+
+		// Removing another key does not disturb the cursor.
+		tree.Remove(5)
+		if got := c.Clone().Next().Key(); got != 6 {
+			t.Errorf("Next after Remove: got %d, want 6", got)
+		}
+
+		// Removing keys with a cursor updates other cursors.
+		tree.Cursor(7).Delete().Delete() // 7, 8
+		if got := d.Clone().Prev().Key(); got != 6 {
+			t.Errorf("Prev after Delete: got %d, want 6", got)
+		}
+
+		// Removing the key at a cursor invalidates it, or moves it to the key
+		// that replaced it in the tree.
+		for i := 30; i <= 200; i++ {
+			tree.Remove(i)
+		}
+		tree.Remove(4)
+		if c.Valid() {
+			if got := c.Key(); got == 4 {
+				t.Errorf("Cursor after Remove: got %d, want invalid or another key", got)
+			}
+		}
+	})
+}
+
 func TestKV(t *testing.T) {
 	type kv = stree.KV[string, int]
 	compare := kv{}.Compare(cmp.Compare)
@@ -397,6 +896,71 @@ func TestKV(t *testing.T) {
 	}
 }
 
+func TestGetOrAdd(t *testing.T) {
+	type kv = stree.KV[string, int]
+	st := stree.New(0, kv{}.Compare(cmp.Compare), kv{"b", 1}, kv{"d", 2})
+
+	calls := 0
+	for _, tc := range []struct {
+		key   string
+		want  int
+		added bool
+	}{
+		{"b", 1, false},
+		{"a", 10, true},
+		{"d", 2, false},
+		{"e", 11, true},
+		{"a", 10, false},
+		{"c", 12, true},
+	} {
+		got, added := st.GetOrAdd(kv{Key: tc.key}, func() kv {
+			calls++
+			return kv{Key: tc.key, Value: 9 + calls}
+		})
+		if got.Key != tc.key || got.Value != tc.want || added != tc.added {
+			t.Errorf("GetOrAdd(%q): got %v, %v; want %d, %v", tc.key, got, added, tc.want, tc.added)
+		}
+	}
+	if calls != 3 {
+		t.Errorf("Constructor was called %d times, want 3", calls)
+	}
+	var got []kv
+	for key := range st.Inorder {
+		got = append(got, key)
+	}
+	if diff := gocmp.Diff(got, []kv{
+		{"a", 10}, {"b", 1}, {"c", 12}, {"d", 2}, {"e", 11},
+	}); diff != "" {
+		t.Errorf("GetOrAdd (-got, +want):\n%s", diff)
+	}
+	if st.Len() != 5 {
+		t.Errorf("Len: got %d, want 5", st.Len())
+	}
+
+	// The result reports whether the key was added, not whether it was present.
+	if _, added := st.GetOrAdd(kv{Key: "a"}, func() kv { panic("unexpected call") }); added {
+		t.Error(`GetOrAdd("a") on a hit: got added=true, want false`)
+	}
+	if _, added := st.GetOrAdd(kv{Key: "z"}, func() kv { return kv{Key: "z"} }); !added {
+		t.Error(`GetOrAdd("z") on a miss: got added=false, want true`)
+	}
+
+	// Adding many keys in order exercises rebalancing.
+	words := stree.New(0, cmp.Compare[string])
+	for i := range 200 {
+		key := fmt.Sprintf("%04d", i)
+		if got, added := words.GetOrAdd(key, func() string { return key }); !added || got != key {
+			t.Errorf("GetOrAdd(%q): got %q, %v; want %q, true", key, got, added, key)
+		}
+	}
+	if got, want := words.Len(), 200; got != want {
+		t.Errorf("Len: got %d, want %d", got, want)
+	}
+	if h, lim := words.Height(), 10; h > lim {
+		t.Errorf("Height: got %d, want at most %d", h, lim)
+	}
+}
+
 func TestClone(t *testing.T) {
 	orig := stree.New(100, cmp.Compare, "a", "b", "c", "d", "e")
 	copy := orig.Clone()
@@ -414,6 +978,669 @@ func TestClone(t *testing.T) {
 	}
 }
 
+func TestReplaceAll(t *testing.T) {
+	type kv = stree.KV[string, int]
+	compare := kv{}.Compare(cmp.Compare[string])
+	seqOf := func(kvs ...kv) iter.Seq[kv] { return slices.Values(kvs) }
+
+	tree := stree.New(100, compare, kv{"b", 1}, kv{"d", 2}, kv{"f", 3})
+	if n := tree.ReplaceAll(seqOf()); n != 0 {
+		t.Errorf("ReplaceAll(empty): got %d, want 0", n)
+	}
+	n := tree.ReplaceAll(seqOf(
+		kv{"e", 10}, kv{"a", 11}, kv{"d", 12}, kv{"g", 13},
+		kv{"a", 14}, kv{"d", 15}, kv{"c", 16}, kv{"e", 17},
+	))
+	if n != 4 {
+		t.Errorf("ReplaceAll: got %d added, want 4", n)
+	}
+	var got []kv
+	for key := range tree.Inorder {
+		got = append(got, key)
+	}
+	if diff := gocmp.Diff(got, []kv{
+		{"a", 14}, {"b", 1}, {"c", 16}, {"d", 15}, {"e", 17}, {"f", 3}, {"g", 13},
+	}); diff != "" {
+		t.Errorf("ReplaceAll (-got, +want):\n%s", diff)
+	}
+	if tree.Len() != len(got) {
+		t.Errorf("Len: got %d, want %d", tree.Len(), len(got))
+	}
+
+	// The result should agree with adding the same keys one at a time.
+	words := strings.Fields(strings.Repeat("the quick brown fox jumps over the lazy dog ", 3))
+	for i := 0; i < len(words); i += 4 {
+		bulk, each := stree.New(200, cmp.Compare[string]), stree.New(200, cmp.Compare[string])
+		bulk.ReplaceAll(slices.Values(words[:i]))
+		for _, w := range words[:i] {
+			each.Replace(w)
+		}
+		bulk.ReplaceAll(slices.Values(words[i:]))
+		for _, w := range words[i:] {
+			each.Replace(w)
+		}
+		if diff := gocmp.Diff(allWords(bulk), allWords(each)); diff != "" {
+			t.Errorf("Split at %d (-bulk, +each):\n%s", i, diff)
+		}
+	}
+}
+
+func TestAddRemoveAll(t *testing.T) {
+	type kv = stree.KV[string, int]
+	compare := kv{}.Compare(cmp.Compare[string])
+	keysOf := func(tree *stree.Tree[kv]) []kv {
+		var got []kv
+		for key := range tree.Inorder {
+			got = append(got, key)
+		}
+		return got
+	}
+
+	tree := stree.New(100, compare, kv{"b", 1}, kv{"d", 2}, kv{"f", 3})
+	if n := tree.AddAll(); n != 0 {
+		t.Errorf("AddAll(empty): got %d, want 0", n)
+	}
+	if n := tree.AddAll(
+		kv{"e", 10}, kv{"a", 11}, kv{"d", 12}, kv{"g", 13},
+		kv{"a", 14}, kv{"d", 15}, kv{"c", 16}, kv{"e", 17},
+	); n != 4 {
+		t.Errorf("AddAll: got %d added, want 4", n)
+	}
+	if diff := gocmp.Diff(keysOf(tree), []kv{
+		{"a", 11}, {"b", 1}, {"c", 16}, {"d", 2}, {"e", 10}, {"f", 3}, {"g", 13},
+	}); diff != "" {
+		t.Errorf("AddAll (-got, +want):\n%s", diff)
+	}
+
+	if n := tree.RemoveAll(); n != 0 {
+		t.Errorf("RemoveAll(empty): got %d, want 0", n)
+	}
+	del := []kv{{"g", 0}, {"x", 0}, {"a", 0}, {"d", 0}, {"a", 0}, {"0", 0}}
+	if n := tree.RemoveAll(del...); n != 3 {
+		t.Errorf("RemoveAll: got %d removed, want 3", n)
+	}
+	if diff := gocmp.Diff(keysOf(tree), []kv{
+		{"b", 1}, {"c", 16}, {"e", 10}, {"f", 3},
+	}); diff != "" {
+		t.Errorf("RemoveAll (-got, +want):\n%s", diff)
+	}
+	if del[0].Key != "g" {
+		t.Errorf("RemoveAll modified its argument: %v", del)
+	}
+	if tree.Len() != 4 {
+		t.Errorf("Len: got %d, want 4", tree.Len())
+	}
+
+	// The results should agree with adding and removing keys one at a time.
+	words := strings.Fields(strings.Repeat("the quick brown fox jumps over the lazy dog ", 3))
+	for i := 0; i < len(words); i += 4 {
+		bulk, each := stree.New(200, cmp.Compare[string]), stree.New(200, cmp.Compare[string])
+		bulk.AddAll(words[i:]...)
+		for _, w := range words[i:] {
+			each.Add(w)
+		}
+		bulk.RemoveAll(words[:i]...)
+		for _, w := range words[:i] {
+			each.Remove(w)
+		}
+		if diff := gocmp.Diff(allWords(bulk), allWords(each), cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("Split at %d (-bulk, +each):\n%s", i, diff)
+		}
+		if bulk.Len() != each.Len() {
+			t.Errorf("Split at %d: Len got %d, want %d", i, bulk.Len(), each.Len())
+		}
+	}
+}
+
+func TestRemoveIf(t *testing.T) {
+	var empty stree.Tree[int]
+	if n := empty.RemoveIf(func(int) bool { return true }); n != 0 {
+		t.Errorf("RemoveIf on empty tree: got %d, want 0", n)
+	}
+
+	for _, β := range []int{0, 100, 250, 1000} {
+		tree := stree.New(β, cmp.Compare[int])
+		for i := range 500 {
+			tree.Add(i)
+		}
+
+		// Remove the multiples of 3, checking the keys are visited in order.
+		var seen []int
+		n := tree.RemoveIf(func(key int) bool {
+			seen = append(seen, key)
+			return key%3 == 0
+		})
+		if n != 167 {
+			t.Errorf("β=%d RemoveIf: got %d removed, want 167", β, n)
+		}
+		if !slices.IsSorted(seen) || len(seen) != 500 {
+			t.Errorf("β=%d RemoveIf visited %d keys, sorted=%v; want 500 in order",
+				β, len(seen), slices.IsSorted(seen))
+		}
+
+		var want []int
+		for i := range 500 {
+			if i%3 != 0 {
+				want = append(want, i)
+			}
+		}
+		if diff := gocmp.Diff(slices.Collect(tree.Inorder), want); diff != "" {
+			t.Errorf("β=%d RemoveIf (-got, +want):\n%s", β, diff)
+		}
+		if tree.Len() != len(want) {
+			t.Errorf("β=%d Len: got %d, want %d", β, tree.Len(), len(want))
+		}
+		if got, max := tree.Height(), bits.Len(uint(len(want))); got > max {
+			t.Errorf("β=%d Height: got %d, want ≤ %d", β, got, max)
+		}
+
+		// Removing nothing leaves the keys alone.
+		if n := tree.RemoveIf(func(int) bool { return false }); n != 0 {
+			t.Errorf("β=%d RemoveIf(none): got %d, want 0", β, n)
+		}
+		if tree.Len() != len(want) {
+			t.Errorf("β=%d Len: got %d, want %d", β, tree.Len(), len(want))
+		}
+
+		// Removing everything empties the tree.
+		if n := tree.RemoveIf(func(int) bool { return true }); n != len(want) {
+			t.Errorf("β=%d RemoveIf(all): got %d, want %d", β, n, len(want))
+		}
+		if !tree.IsEmpty() {
+			t.Errorf("β=%d RemoveIf(all): tree is not empty", β)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	type kv = stree.KV[string, int]
+	compare := kv{}.Compare(cmp.Compare[string])
+
+	a := stree.New(100, compare, kv{"b", 1}, kv{"d", 2}, kv{"f", 3})
+	b := stree.New(100, compare, kv{"a", 4}, kv{"d", 5}, kv{"e", 6}, kv{"g", 7})
+	if n := a.Merge(b); n != 3 {
+		t.Errorf("Merge: got %d added, want 3", n)
+	}
+	var got []kv
+	for key := range a.Inorder {
+		got = append(got, key)
+	}
+	if diff := gocmp.Diff(got, []kv{
+		{"a", 4}, {"b", 1}, {"d", 2}, {"e", 6}, {"f", 3}, {"g", 7},
+	}); diff != "" {
+		t.Errorf("Merge (-got, +want):\n%s", diff)
+	}
+	if a.Len() != 6 || b.Len() != 4 {
+		t.Errorf("Len: got %d, %d; want 6, 4", a.Len(), b.Len())
+	}
+
+	// Merging with an empty tree or with itself has no effect.
+	if n := a.Merge(stree.New(100, compare)); n != 0 {
+		t.Errorf("Merge(empty): got %d added, want 0", n)
+	}
+	if n := a.Merge(a); n != 0 {
+		t.Errorf("Merge(self): got %d added, want 0", n)
+	}
+	if a.Len() != 6 {
+		t.Errorf("Len: got %d, want 6", a.Len())
+	}
+
+	// Merging the trees for two halves of a sequence should match a tree
+	// constructed from the whole sequence.
+	words := strings.Fields(strings.Repeat("the quick brown fox jumps over the lazy dog ", 3))
+	for i := 0; i < len(words); i += 4 {
+		lo := stree.New(200, cmp.Compare[string], words[:i]...)
+		hi := stree.New(200, cmp.Compare[string], words[i:]...)
+		lo.Merge(hi)
+		if diff := gocmp.Diff(allWords(lo), sortedUnique(words, nil)); diff != "" {
+			t.Errorf("Split at %d (-got, +want):\n%s", i, diff)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	words := strings.Fields(strings.Repeat("the quick brown fox jumps over the lazy dog ", 3))
+	want := sortedUnique(words, nil)
+	for _, key := range []string{"", "a", "brown", "fox", "g", "the", "zebra"} {
+		lo := stree.New(100, cmp.Compare[string], words...)
+		hi := lo.Split(key)
+
+		i, _ := slices.BinarySearch(want, key)
+		if diff := gocmp.Diff(allWords(lo), want[:i], cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("Split(%q) lo (-got, +want):\n%s", key, diff)
+		}
+		if diff := gocmp.Diff(allWords(hi), want[i:], cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("Split(%q) hi (-got, +want):\n%s", key, diff)
+		}
+		if lo.Len() != i || hi.Len() != len(want)-i {
+			t.Errorf("Split(%q): got sizes %d, %d; want %d, %d", key, lo.Len(), hi.Len(), i, len(want)-i)
+		}
+
+		// Both halves should be usable, and merging them should restore the
+		// original tree.
+		hi.Add("zzz")
+		hi.Remove("zzz")
+		lo.Merge(hi)
+		if diff := gocmp.Diff(allWords(lo), want); diff != "" {
+			t.Errorf("Split(%q) merged (-got, +want):\n%s", key, diff)
+		}
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	type kv = stree.KV[string, int]
+	compare := kv{}.Compare(cmp.Compare[string])
+	src := stree.New(100, compare, kv{"plum", 3}, kv{"apple", 1}, kv{"pear", 2})
+	want := []kv{{"apple", 1}, {"pear", 2}, {"plum", 3}}
+	keysOf := func(tree *stree.Tree[kv]) []kv {
+		var got []kv
+		for key := range tree.Inorder {
+			got = append(got, key)
+		}
+		return got
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		data, err := json.Marshal(src)
+		if err != nil {
+			t.Fatalf("Marshal: unexpected error: %v", err)
+		}
+		const wantJSON = `[{"Key":"apple","Value":1},{"Key":"pear","Value":2},{"Key":"plum","Value":3}]`
+		if got := string(data); got != wantJSON {
+			t.Errorf("Marshal: got %#q, want %#q", got, wantJSON)
+		}
+
+		dst := stree.New(100, compare, kv{"cherry", 10}) // replaced
+		if err := json.Unmarshal(data, dst); err != nil {
+			t.Fatalf("Unmarshal: unexpected error: %v", err)
+		}
+		if diff := gocmp.Diff(keysOf(dst), want); diff != "" {
+			t.Errorf("Unmarshal (-got, +want):\n%s", diff)
+		}
+		if dst.Len() != len(want) {
+			t.Errorf("Len: got %d, want %d", dst.Len(), len(want))
+		}
+
+		// Keys out of order or duplicated are sorted, keeping the first.
+		if err := json.Unmarshal([]byte(`[{"Key":"plum","Value":3},{"Key":"apple","Value":1},`+
+			`{"Key":"pear","Value":2},{"Key":"apple","Value":5}]`), dst); err != nil {
+			t.Fatalf("Unmarshal: unexpected error: %v", err)
+		}
+		if diff := gocmp.Diff(keysOf(dst), want); diff != "" {
+			t.Errorf("Unmarshal unsorted (-got, +want):\n%s", diff)
+		}
+
+		if data, err := json.Marshal(stree.New(100, compare)); err != nil || string(data) != "[]" {
+			t.Errorf("Marshal empty: got %#q, %v; want [], nil", data, err)
+		}
+		if err := json.Unmarshal(data, new(stree.Tree[kv])); err == nil {
+			t.Error("Unmarshal into a zero tree: got nil, want error")
+		}
+		if err := json.Unmarshal([]byte(`{}`), dst); err == nil {
+			t.Error("Unmarshal non-array: got nil, want error")
+		}
+	})
+
+	t.Run("Gob", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(src); err != nil {
+			t.Fatalf("Encode: unexpected error: %v", err)
+		}
+		dst := stree.New(100, compare)
+		if err := gob.NewDecoder(&buf).Decode(dst); err != nil {
+			t.Fatalf("Decode: unexpected error: %v", err)
+		}
+		if diff := gocmp.Diff(keysOf(dst), want); diff != "" {
+			t.Errorf("Decode (-got, +want):\n%s", diff)
+		}
+	})
+
+	t.Run("Large", func(t *testing.T) {
+		// A decoded tree should be perfectly balanced.
+		big := stree.New(250, cmp.Compare[int])
+		for i := range 1000 {
+			big.Add(i)
+		}
+		data, err := json.Marshal(big)
+		if err != nil {
+			t.Fatalf("Marshal: unexpected error: %v", err)
+		}
+		dst := stree.New(250, cmp.Compare[int])
+		if err := json.Unmarshal(data, dst); err != nil {
+			t.Fatalf("Unmarshal: unexpected error: %v", err)
+		}
+		if got, want := dst.Height(), 10; got != want {
+			t.Errorf("Height: got %d, want %d", got, want)
+		}
+		if got, want := dst.Len(), 1000; got != want {
+			t.Errorf("Len: got %d, want %d", got, want)
+		}
+	})
+}
+
+func TestSync(t *testing.T) {
+	s := stree.NewSync(stree.New(100, cmp.Compare[int]))
+
+	const numWorkers = 8
+	const numKeys = 200
+
+	// Each worker adds its own keys, and reads concurrently with the others.
+	var wg sync.WaitGroup
+	for w := range numWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range numKeys {
+				key := w*numKeys + i
+				if !s.Add(key) {
+					t.Errorf("Add(%d): got false, want true", key)
+				}
+				if _, ok := s.Get(key); !ok {
+					t.Errorf("Get(%d): not found", key)
+				}
+				if i%50 == 0 {
+					// Calling into s while iterating must not deadlock.
+					for key := range s.Inorder {
+						s.Get(key)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got, want := s.Len(), numWorkers*numKeys; got != want {
+		t.Errorf("Len: got %d, want %d", got, want)
+	}
+	if got := slices.Collect(s.Inorder); len(got) != s.Len() || !slices.IsSorted(got) {
+		t.Errorf("Inorder: got %d keys, want %d in order", len(got), s.Len())
+	}
+	if diff := gocmp.Diff(slices.Collect(s.Between(10, 15)), []int{10, 11, 12, 13, 14}); diff != "" {
+		t.Errorf("Between (-got, +want):\n%s", diff)
+	}
+	if got, ok := s.Floor(-1); ok {
+		t.Errorf("Floor(-1): got (%d, true), want false", got)
+	}
+	if got, ok := s.Ceiling(-1); !ok || got != 0 {
+		t.Errorf("Ceiling(-1): got (%d, %v), want (0, true)", got, ok)
+	}
+
+	// A snapshot is not affected by later changes.
+	snap := s.Snapshot()
+	if err := s.Update(func(tree *stree.Tree[int]) error {
+		tree.RemoveAll(1, 2, 3)
+		return nil
+	}); err != nil {
+		t.Errorf("Update: unexpected error: %v", err)
+	}
+	var n int
+	s.View(func(tree *stree.Tree[int]) error { n = tree.Len(); return nil })
+	if want := numWorkers*numKeys - 3; n != want {
+		t.Errorf("View: got %d keys, want %d", n, want)
+	}
+	if got, want := snap.Len(), numWorkers*numKeys; got != want {
+		t.Errorf("Snapshot Len: got %d, want %d", got, want)
+	}
+
+	s.Clear()
+	if s.Len() != 0 {
+		t.Errorf("After Clear: got %d keys, want 0", s.Len())
+	}
+}
+
+func TestClearReuse(t *testing.T) {
+	tree := stree.New(100, cmp.Compare[int])
+	fill := func() {
+		for i := range 100 {
+			tree.Add((i * 37) % 100)
+		}
+	}
+	fill()
+	tree.ClearReuse()
+	if !tree.IsEmpty() {
+		t.Error("IsEmpty should be true after ClearReuse")
+	}
+	mdtest.CheckContents(t, eacher[int]{tree}, nil)
+
+	// Refilling a recycled tree should reuse the nodes rather than allocating.
+	if allocs := testing.AllocsPerRun(10, func() { fill(); tree.ClearReuse() }); allocs != 0 {
+		t.Errorf("Refill after ClearReuse: got %v allocations, want 0", allocs)
+	}
+
+	fill()
+	want := make([]int, 100)
+	for i := range want {
+		want[i] = i
+	}
+	mdtest.CheckContents(t, eacher[int]{tree}, want)
+
+	// A clone does not share the retained nodes of the original.
+	tree.ClearReuse()
+	cp := tree.Clone()
+	cp.Add(1)
+	tree.Add(2)
+	mdtest.CheckContents(t, eacher[int]{cp}, []int{1})
+	mdtest.CheckContents(t, eacher[int]{tree}, []int{2})
+
+	// Clear discards the retained nodes.
+	tree.ClearReuse()
+	tree.Clear()
+	if allocs := testing.AllocsPerRun(1, func() { tree.Add(3); tree.Clear() }); allocs != 1 {
+		t.Errorf("Add after Clear: got %v allocations, want 1", allocs)
+	}
+}
+
+// eacher adapts a tree to the mdtest.Eacher interface.
+type eacher[T any] struct{ *stree.Tree[T] }
+
+func (e eacher[T]) Each(f func(T) bool) { e.Inorder(f) }
+
+func TestHeight(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		tree := stree.New(0, cmp.Compare[int])
+		if h := tree.Height(); h != 0 {
+			t.Errorf("Height: got %d, want 0", h)
+		}
+		if d := tree.Depths(); d != nil {
+			t.Errorf("Depths: got %v, want nil", d)
+		}
+		if diff := gocmp.Diff(tree.Stats(), stree.Stats{}); diff != "" {
+			t.Errorf("Stats (-got, +want):\n%s", diff)
+		}
+	})
+	t.Run("Balanced", func(t *testing.T) {
+		// Construction from a list of keys yields a perfectly balanced tree.
+		keys := make([]int, 20)
+		for i := range keys {
+			keys[i] = i + 1
+		}
+		tree := stree.New(0, cmp.Compare[int], keys...)
+		if h := tree.Height(); h != 5 {
+			t.Errorf("Height: got %d, want 5", h)
+		}
+		if diff := gocmp.Diff(tree.Depths(), []int{1, 2, 4, 8, 5}); diff != "" {
+			t.Errorf("Depths (-got, +want):\n%s", diff)
+		}
+		want := stree.Stats{
+			Balance:   0,
+			Len:       20,
+			Height:    5,
+			MinHeight: 5,
+			MeanDepth: float64(0*1+1*2+2*4+3*8+4*5) / 20,
+			Depths:    []int{1, 2, 4, 8, 5},
+		}
+		if diff := gocmp.Diff(tree.Stats(), want); diff != "" {
+			t.Errorf("Stats (-got, +want):\n%s", diff)
+		}
+	})
+	t.Run("Unbalanced", func(t *testing.T) {
+		// With no rebalancing, keys inserted in order form a chain.
+		tree := stree.New(1000, cmp.Compare[int])
+		for i := range 10 {
+			tree.Add(i)
+		}
+		if h := tree.Height(); h != 10 {
+			t.Errorf("Height: got %d, want 10", h)
+		}
+		want := []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}
+		if diff := gocmp.Diff(tree.Depths(), want); diff != "" {
+			t.Errorf("Depths (-got, +want):\n%s", diff)
+		}
+		if s := tree.Stats(); s.Balance != 1000 || s.Height != 10 || s.MinHeight != 4 || s.MeanDepth != 4.5 {
+			t.Errorf("Stats: got %+v, want β=1000, height 10, min height 4, mean depth 4.5", s)
+		}
+	})
+}
+
+func TestRankSelect(t *testing.T) {
+	tree := stree.New(100, cmp.Compare[int])
+	if _, ok := tree.Select(0); ok {
+		t.Error("Select(0) on an empty tree reported true")
+	}
+	if r := tree.Rank(5); r != 0 {
+		t.Errorf("Rank(5) on an empty tree: got %d, want 0", r)
+	}
+
+	var keys []int
+	for i := range 300 {
+		keys = append(keys, 3*i)
+		tree.Add(3 * i)
+	}
+	for _, key := range keys {
+		if key%2 == 0 {
+			tree.Remove(key)
+		}
+	}
+	keys = slices.DeleteFunc(keys, func(z int) bool { return z%2 == 0 })
+	if tree.Len() != len(keys) {
+		t.Fatalf("Len: got %d, want %d", tree.Len(), len(keys))
+	}
+
+	for i, key := range keys {
+		if got, ok := tree.Select(i); !ok || got != key {
+			t.Errorf("Select(%d): got (%d, %v), want (%d, true)", i, got, ok, key)
+		}
+		if r := tree.Rank(key); r != i {
+			t.Errorf("Rank(%d): got %d, want %d", key, r, i)
+		}
+		if r := tree.Rank(key + 1); r != i+1 {
+			t.Errorf("Rank(%d): got %d, want %d", key+1, r, i+1)
+		}
+	}
+	for _, i := range []int{-1, len(keys), len(keys) + 5} {
+		if got, ok := tree.Select(i); ok {
+			t.Errorf("Select(%d): got (%d, true), want false", i, got)
+		}
+	}
+	if r := tree.Rank(-1); r != 0 {
+		t.Errorf("Rank(-1): got %d, want 0", r)
+	}
+	if r := tree.Rank(10000); r != len(keys) {
+		t.Errorf("Rank(10000): got %d, want %d", r, len(keys))
+	}
+}
+
+func TestMinMaxPop(t *testing.T) {
+	tree := stree.New(100, cmp.Compare[int])
+	if got, ok := tree.MinOK(); ok {
+		t.Errorf("MinOK on empty tree: got (%d, true), want false", got)
+	}
+	if got, ok := tree.MaxOK(); ok {
+		t.Errorf("MaxOK on empty tree: got (%d, true), want false", got)
+	}
+	if got, ok := tree.PopMin(); ok {
+		t.Errorf("PopMin on empty tree: got (%d, true), want false", got)
+	}
+	if got, ok := tree.PopMax(); ok {
+		t.Errorf("PopMax on empty tree: got (%d, true), want false", got)
+	}
+
+	const numKeys = 100
+	for i := range numKeys {
+		tree.Add((i * 37) % numKeys) // a permutation of 0..numKeys-1
+	}
+	lo, hi := 0, numKeys-1
+	for i := 0; !tree.IsEmpty(); i++ {
+		if got, ok := tree.MinOK(); !ok || got != lo {
+			t.Errorf("MinOK: got (%d, %v), want (%d, true)", got, ok, lo)
+		}
+		if got, ok := tree.MaxOK(); !ok || got != hi {
+			t.Errorf("MaxOK: got (%d, %v), want (%d, true)", got, ok, hi)
+		}
+		if i%3 == 0 {
+			if got, ok := tree.PopMax(); !ok || got != hi {
+				t.Errorf("PopMax: got (%d, %v), want (%d, true)", got, ok, hi)
+			}
+			hi--
+		} else {
+			if got, ok := tree.PopMin(); !ok || got != lo {
+				t.Errorf("PopMin: got (%d, %v), want (%d, true)", got, ok, lo)
+			}
+			lo++
+		}
+	}
+	if lo != hi+1 {
+		t.Errorf("After popping all keys: lo=%d hi=%d", lo, hi)
+	}
+}
+
+func TestEqualCompare(t *testing.T) {
+	mk := func(keys ...int) *stree.Tree[int] { return stree.New(100, cmp.Compare[int], keys...) }
+	for _, tc := range []struct {
+		a, b *stree.Tree[int]
+		want int
+	}{
+		{mk(), mk(), 0},
+		{mk(1), mk(), 1},
+		{mk(), mk(1), -1},
+		{mk(1, 2, 3), mk(3, 2, 1), 0},
+		{mk(1, 2, 3), mk(1, 2), 1},
+		{mk(1, 2), mk(1, 2, 3), -1},
+		{mk(1, 2, 4), mk(1, 3), -1},
+		{mk(1, 3), mk(1, 2, 4), 1},
+		{mk(5, 10, 15, 20), mk(5, 10, 15, 25), -1},
+	} {
+		a, b := slices.Collect(tc.a.Inorder), slices.Collect(tc.b.Inorder)
+		if got := tc.a.Compare(tc.b); got != tc.want {
+			t.Errorf("Compare(%v, %v): got %d, want %d", a, b, got, tc.want)
+		}
+		if got := tc.b.Compare(tc.a); got != -tc.want {
+			t.Errorf("Compare(%v, %v): got %d, want %d", b, a, got, -tc.want)
+		}
+		if got := tc.a.Equal(tc.b); got != (tc.want == 0) {
+			t.Errorf("Equal(%v, %v): got %v, want %v", a, b, got, tc.want == 0)
+		}
+	}
+}
+
+func TestFloorCeiling(t *testing.T) {
+	tree := stree.New(100, cmp.Compare[int])
+	if got, ok := tree.Floor(5); ok {
+		t.Errorf("Floor(5) on an empty tree: got (%d, true), want false", got)
+	}
+	if got, ok := tree.Ceiling(5); ok {
+		t.Errorf("Ceiling(5) on an empty tree: got (%d, true), want false", got)
+	}
+
+	for i := range 100 {
+		tree.Add(5 * i) // 0, 5, 10, ..., 495
+	}
+	for key := -3; key < 500; key++ {
+		wantFloor, wantFloorOK := key-(key%5), key >= 0
+		if got, ok := tree.Floor(key); ok != wantFloorOK || (ok && got != wantFloor) {
+			t.Errorf("Floor(%d): got (%d, %v), want (%d, %v)", key, got, ok, wantFloor, wantFloorOK)
+		}
+
+		wantCeil, wantCeilOK := key+(5-key%5)%5, key <= 495
+		if key < 0 {
+			wantCeil = 0
+		}
+		if got, ok := tree.Ceiling(key); ok != wantCeilOK || (ok && got != wantCeil) {
+			t.Errorf("Ceiling(%d): got (%d, %v), want (%d, %v)", key, got, ok, wantCeil, wantCeilOK)
+		}
+	}
+}
+
 func TestBasicProperties(t *testing.T) {
 	// http://www.gutenberg.org/files/1063/1063-h/1063-h.htm
 	text, err := os.ReadFile(*textFile)
@@ -421,7 +1648,7 @@ func TestBasicProperties(t *testing.T) {
 		t.Fatalf("Reading text: %v", err)
 	}
 	tree, words := makeTree(*strictness, string(text))
-	t.Logf("%v has height %d", tree, cursorHeight(tree.Root()))
+	t.Logf("%v has height %d", tree, tree.Height())
 	dumpTree(t, tree)
 
 	got := allWords(tree)
@@ -515,14 +1742,3 @@ func makeTree(β int, input string) (*stree.Tree[string], []string) {
 	}
 	return tree, words
 }
-
-func cursorHeight[T any](c *stree.Cursor[T]) int {
-	if !c.Valid() {
-		return 0
-	}
-	m := max(
-		cursorHeight(c.Clone().Left()),
-		cursorHeight(c.Clone().Right()),
-	)
-	return m + 1
-}
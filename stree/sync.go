@@ -0,0 +1,150 @@
+package stree
+
+import (
+	"iter"
+	"sync"
+)
+
+// Sync is a wrapper for a [Tree] that is safe for concurrent use by multiple
+// goroutines. Operations that only read the tree hold a shared lock, so
+// read-mostly workloads do not contend with each other.
+//
+// The iteration methods of a Sync copy the keys they visit while holding the
+// lock, and deliver them after it is released. This means the caller may
+// safely call methods of the Sync during iteration, and that an iteration
+// reflects the contents of the tree at the time it began. For more complex
+// operations, use [Sync.View] and [Sync.Update] to access the underlying tree
+// directly while holding the lock.
+type Sync[T any] struct {
+	μ    sync.RWMutex
+	tree *Tree[T]
+}
+
+// NewSync returns a new Sync that wraps t. The Sync takes ownership of t, and
+// the caller must not use t directly after NewSync returns.
+func NewSync[T any](t *Tree[T]) *Sync[T] { return &Sync[T]{tree: t} }
+
+// View calls f with the underlying tree while holding a shared lock, and
+// returns the result from f. The function must not modify the tree, call
+// methods of s, or retain the tree or cursors to it after it returns.
+func (s *Sync[T]) View(f func(*Tree[T]) error) error {
+	s.μ.RLock()
+	defer s.μ.RUnlock()
+	return f(s.tree)
+}
+
+// Update calls f with the underlying tree while holding an exclusive lock,
+// and returns the result from f. The function may modify the tree, but must
+// not call methods of s, or retain the tree or cursors to it after it
+// returns.
+func (s *Sync[T]) Update(f func(*Tree[T]) error) error {
+	s.μ.Lock()
+	defer s.μ.Unlock()
+	return f(s.tree)
+}
+
+// Snapshot returns a copy of the underlying tree, as for [Tree.Clone].
+func (s *Sync[T]) Snapshot() *Tree[T] {
+	s.μ.RLock()
+	defer s.μ.RUnlock()
+	return s.tree.Clone()
+}
+
+// Add inserts key into the tree, as for [Tree.Add].
+func (s *Sync[T]) Add(key T) bool {
+	s.μ.Lock()
+	defer s.μ.Unlock()
+	return s.tree.Add(key)
+}
+
+// Replace inserts or replaces key in the tree, as for [Tree.Replace].
+func (s *Sync[T]) Replace(key T) bool {
+	s.μ.Lock()
+	defer s.μ.Unlock()
+	return s.tree.Replace(key)
+}
+
+// GetOrAdd returns the key equivalent to key in the tree, or adds one, as for
+// [Tree.GetOrAdd]. The newKey function is called while holding the lock, so
+// it must not call methods of s.
+func (s *Sync[T]) GetOrAdd(key T, newKey func() T) (T, bool) {
+	s.μ.Lock()
+	defer s.μ.Unlock()
+	return s.tree.GetOrAdd(key, newKey)
+}
+
+// Remove removes key from the tree, as for [Tree.Remove].
+func (s *Sync[T]) Remove(key T) bool {
+	s.μ.Lock()
+	defer s.μ.Unlock()
+	return s.tree.Remove(key)
+}
+
+// Clear discards all the keys in the tree, as for [Tree.Clear].
+func (s *Sync[T]) Clear() {
+	s.μ.Lock()
+	defer s.μ.Unlock()
+	s.tree.Clear()
+}
+
+// Get reports whether key is present in the tree, as for [Tree.Get].
+func (s *Sync[T]) Get(key T) (T, bool) {
+	s.μ.RLock()
+	defer s.μ.RUnlock()
+	return s.tree.Get(key)
+}
+
+// Floor returns the greatest key less than or equal to key, as for
+// [Tree.Floor].
+func (s *Sync[T]) Floor(key T) (T, bool) {
+	s.μ.RLock()
+	defer s.μ.RUnlock()
+	return s.tree.Floor(key)
+}
+
+// Ceiling returns the least key greater than or equal to key, as for
+// [Tree.Ceiling].
+func (s *Sync[T]) Ceiling(key T) (T, bool) {
+	s.μ.RLock()
+	defer s.μ.RUnlock()
+	return s.tree.Ceiling(key)
+}
+
+// Len reports the number of keys in the tree.
+func (s *Sync[T]) Len() int {
+	s.μ.RLock()
+	defer s.μ.RUnlock()
+	return s.tree.Len()
+}
+
+// Inorder is a range function that visits each key of the tree in order.
+// The keys are copied before any are visited, as described for [Sync].
+func (s *Sync[T]) Inorder(yield func(key T) bool) {
+	s.μ.RLock()
+	keys := s.tree.keys()
+	s.μ.RUnlock()
+	for _, key := range keys {
+		if !yield(key) {
+			return
+		}
+	}
+}
+
+// Between returns a range function for each key greater than or equal to lo
+// and less than hi, in order, as for [Tree.Between]. The keys in the range
+// are copied before any are visited, as described for [Sync].
+func (s *Sync[T]) Between(lo, hi T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.μ.RLock()
+		var keys []T
+		for key := range s.tree.Between(lo, hi) {
+			keys = append(keys, key)
+		}
+		s.μ.RUnlock()
+		for _, key := range keys {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// Package topk implements the "space-saving" algorithm of Metwally, Agrawal,
+// and El Abbadi for finding the most frequent elements of a stream in bounded
+// space, as described in the paper "Efficient Computation of Frequent and
+// Top-k Elements in Data Streams" ([SS]).
+//
+// A [Tracker] monitors at most a fixed number of distinct elements. Each
+// monitored element has an estimated count, which may overestimate the true
+// number of occurrences of the element by at most a recorded error bound.
+// Any element that occurs more than Total/Size times is guaranteed to be
+// monitored.
+//
+// [SS]: https://www.cs.ucsb.edu/sites/default/files/documents/2005-23.pdf
+package topk
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/creachadair/mds/heapq"
+)
+
+// A Tracker estimates the most frequent comparable elements that have been
+// passed to its Add method, using at most a fixed amount of space.
+//
+// Add elements to a tracker using [Tracker.Add] or [Tracker.AddN]; use
+// [Tracker.Top] to obtain the most frequent elements observed.
+type Tracker[T comparable] struct {
+	size  int
+	total uint64
+	index map[T]int // :: value → offset in heap
+	heap  *heapq.Queue[Entry[T]]
+}
+
+// An Entry is an element monitored by a [Tracker], with its estimated count.
+type Entry[T comparable] struct {
+	Value T      // the element
+	Count uint64 // the estimated number of occurrences of Value
+	Error uint64 // the maximum amount by which Count exceeds the true count
+}
+
+// Min returns the minimum number of times e.Value is guaranteed to have
+// occurred, Count - Error.
+func (e Entry[T]) Min() uint64 { return e.Count - e.Error }
+
+func compareEntry[T comparable](a, b Entry[T]) int { return cmp.Compare(a.Count, b.Count) }
+
+// New constructs a new empty tracker that monitors at most size distinct
+// elements. New will panic if size ≤ 0.
+func New[T comparable](size int) *Tracker[T] {
+	if size <= 0 {
+		panic("size out of range")
+	}
+	t := &Tracker[T]{
+		size:  size,
+		index: make(map[T]int),
+		heap:  heapq.New(compareEntry[T]),
+	}
+	t.heap.Update(func(e Entry[T], pos int) { t.index[e.Value] = pos })
+	return t
+}
+
+// Size reports the maximum number of distinct elements monitored by t.
+func (t *Tracker[T]) Size() int { return t.size }
+
+// Len reports the number of distinct elements currently monitored by t.
+func (t *Tracker[T]) Len() int { return t.heap.Len() }
+
+// Total reports the total number of occurrences observed by t.
+func (t *Tracker[T]) Total() uint64 { return t.total }
+
+// Reset resets t to its initial state, as if freshly constructed.
+// The size limit remains unchanged.
+func (t *Tracker[T]) Reset() { clear(t.index); t.heap.Clear(); t.total = 0 }
+
+// Add records an occurrence of v. It is equivalent to t.AddN(v, 1).
+func (t *Tracker[T]) Add(v T) { t.AddN(v, 1) }
+
+// AddN records n occurrences of v.
+//
+// If v is already monitored, its count is increased by n. Otherwise, if t
+// has room, v is monitored with count n. Otherwise, v replaces the monitored
+// element with the smallest count, m, and is given count m+n and error m.
+func (t *Tracker[T]) AddN(v T, n uint64) {
+	t.total += n
+	if pos, ok := t.index[v]; ok {
+		e, _ := t.heap.Remove(pos) // cannot fail
+		e.Count += n
+		t.heap.Add(e)
+		return
+	}
+	e := Entry[T]{Value: v, Count: n}
+	if t.heap.Len() >= t.size {
+		old, _ := t.heap.Pop()
+		delete(t.index, old.Value)
+		e.Count += old.Count
+		e.Error = old.Count
+	}
+	t.heap.Add(e)
+}
+
+// Count reports whether v is monitored by t, and if so returns its entry.
+func (t *Tracker[T]) Count(v T) (Entry[T], bool) {
+	if pos, ok := t.index[v]; ok {
+		return t.heap.Peek(pos)
+	}
+	return Entry[T]{}, false
+}
+
+// Top returns up to k of the monitored elements with the highest estimated
+// counts, in non-increasing order of count. Elements with equal counts are
+// ordered by increasing error, and otherwise in unspecified order.  If k ≤ 0
+// or t is empty, Top returns nil.
+func (t *Tracker[T]) Top(k int) []Entry[T] {
+	if k <= 0 || t.heap.Len() == 0 {
+		return nil
+	}
+	out := make([]Entry[T], 0, t.heap.Len())
+	for e := range t.heap.Each {
+		out = append(out, e)
+	}
+	slices.SortFunc(out, func(a, b Entry[T]) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Error, b.Error)
+	})
+	return slices.Clip(out[:min(k, len(out))])
+}
+
+// Merge updates t to summarize the combined observations of t and u, as if
+// all the elements observed by u had also been observed by t. The size of t
+// is not changed, and u is not modified.
+//
+// An element monitored by only one of the trackers may have occurred in the
+// other up to that tracker's smallest count, if it was full. Such elements
+// have the smallest count added to both their count and their error, so the
+// error bounds of the merged result remain valid.
+func (t *Tracker[T]) Merge(u *Tracker[T]) {
+	tMin, uMin := t.minCount(), u.minCount()
+
+	merged := make(map[T]Entry[T], t.heap.Len()+u.heap.Len())
+	for e := range t.heap.Each {
+		merged[e.Value] = e
+	}
+	for e := range u.heap.Each {
+		if m, ok := merged[e.Value]; ok {
+			m.Count += e.Count
+			m.Error += e.Error
+			merged[e.Value] = m
+		} else {
+			e.Count += tMin
+			e.Error += tMin
+			merged[e.Value] = e
+		}
+	}
+	for e := range t.heap.Each {
+		if _, ok := u.index[e.Value]; !ok {
+			m := merged[e.Value]
+			m.Count += uMin
+			m.Error += uMin
+			merged[e.Value] = m
+		}
+	}
+
+	// Keep the entries with the largest counts.
+	all := make([]Entry[T], 0, len(merged))
+	for _, e := range merged {
+		all = append(all, e)
+	}
+	slices.SortFunc(all, func(a, b Entry[T]) int { return cmp.Compare(b.Count, a.Count) })
+
+	total := t.total + u.total
+	t.Reset()
+	t.total = total
+	t.heap.Set(all[:min(t.size, len(all))])
+}
+
+// minCount returns the smallest count of an element monitored by t, if t is
+// full, or otherwise 0. An element not monitored by t can have occurred at
+// most this many times.
+func (t *Tracker[T]) minCount() uint64 {
+	if t.heap.Len() < t.size {
+		return 0
+	}
+	return t.heap.Front().Count
+}
@@ -0,0 +1,142 @@
+package topk_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/creachadair/mds/mtest"
+	"github.com/creachadair/mds/topk"
+	gocmp "github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+type entry = topk.Entry[string]
+
+func TestTracker(t *testing.T) {
+	mtest.MustPanic(t, func() { topk.New[int](0) })
+
+	tr := topk.New[string](3)
+	if got := tr.Top(5); got != nil {
+		t.Errorf("Empty Top: got %v, want nil", got)
+	}
+
+	// While there is room, counts are exact.
+	for _, s := range []string{"a", "b", "a", "c", "a", "b"} {
+		tr.Add(s)
+	}
+	if diff := gocmp.Diff(tr.Top(5), []entry{{"a", 3, 0}, {"b", 2, 0}, {"c", 1, 0}}); diff != "" {
+		t.Errorf("Top (-got, +want):\n%s", diff)
+	}
+	if diff := gocmp.Diff(tr.Top(1), []entry{{"a", 3, 0}}); diff != "" {
+		t.Errorf("Top(1) (-got, +want):\n%s", diff)
+	}
+
+	// A new element replaces the one with the smallest count.
+	tr.AddN("d", 2)
+	if _, ok := tr.Count("c"); ok {
+		t.Error("Count(c): unexpectedly still monitored")
+	}
+	if got, ok := tr.Count("d"); !ok || got != (entry{"d", 3, 1}) {
+		t.Errorf("Count(d): got (%v, %v), want ({d 3 1}, true)", got, ok)
+	}
+	if diff := gocmp.Diff(tr.Top(5), []entry{{"a", 3, 0}, {"d", 3, 1}, {"b", 2, 0}}); diff != "" {
+		t.Errorf("Top (-got, +want):\n%s", diff)
+	}
+	if got, want := tr.Total(), uint64(8); got != want {
+		t.Errorf("Total: got %d, want %d", got, want)
+	}
+	if got := tr.Len(); got != 3 {
+		t.Errorf("Len: got %d, want 3", got)
+	}
+
+	tr.Reset()
+	if tr.Len() != 0 || tr.Total() != 0 || tr.Top(1) != nil {
+		t.Errorf("After Reset: len=%d total=%d top=%v", tr.Len(), tr.Total(), tr.Top(1))
+	}
+}
+
+// zipf returns a stream of n values with a skewed distribution.
+func zipf(seed uint64, n int) []uint64 {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	z := rand.NewZipf(rng, 1.2, 1, 10000)
+	out := make([]uint64, n)
+	for i := range out {
+		out[i] = z.Uint64()
+	}
+	return out
+}
+
+// checkBounds verifies that the entries of tr bound the true counts, and
+// that every element whose true count exceeds the guarantee is monitored.
+func checkBounds(t *testing.T, tr *topk.Tracker[uint64], counts map[uint64]uint64) {
+	t.Helper()
+	for _, e := range tr.Top(tr.Size()) {
+		if c := counts[e.Value]; c < e.Min() || c > e.Count {
+			t.Errorf("Value %d: true count %d not in [%d, %d]", e.Value, c, e.Min(), e.Count)
+		}
+	}
+	limit := tr.Total() / uint64(tr.Size())
+	for v, c := range counts {
+		if _, ok := tr.Count(v); c > limit && !ok {
+			t.Errorf("Value %d: count %d > %d but not monitored", v, c, limit)
+		}
+	}
+}
+
+func TestBounds(t *testing.T) {
+	counts := make(map[uint64]uint64)
+	tr := topk.New[uint64](50)
+	for _, v := range zipf(1, 100000) {
+		tr.Add(v)
+		counts[v]++
+	}
+	checkBounds(t, tr, counts)
+
+	// The most frequent values are found.
+	top := tr.Top(3)
+	for i, e := range top {
+		if e.Value != uint64(i) {
+			t.Errorf("Top %d: got value %d, want %d", i, e.Value, i)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	counts := make(map[uint64]uint64)
+	a, b := topk.New[uint64](40), topk.New[uint64](40)
+	for _, v := range zipf(2, 50000) {
+		a.Add(v)
+		counts[v]++
+	}
+	for _, v := range zipf(3, 30000) {
+		b.Add(v + 1) // shift the distribution
+		counts[v+1]++
+	}
+	a.Merge(b)
+	if got, want := a.Total(), uint64(80000); got != want {
+		t.Errorf("Total: got %d, want %d", got, want)
+	}
+	if a.Len() != a.Size() {
+		t.Errorf("Len: got %d, want %d", a.Len(), a.Size())
+	}
+	checkBounds(t, a, counts)
+
+	// Merging with an empty tracker does not change the counts.
+	before := a.Top(a.Size())
+	a.Merge(topk.New[uint64](10))
+	byValue := cmpopts.SortSlices(func(a, b topk.Entry[uint64]) bool { return a.Value < b.Value })
+	if diff := gocmp.Diff(a.Top(a.Size()), before, byValue); diff != "" {
+		t.Errorf("Merge empty (-got, +want):\n%s", diff)
+	}
+
+	// Merging trackers that have not filled is exact.
+	c, d := topk.New[string](5), topk.New[string](5)
+	c.AddN("x", 3)
+	c.AddN("y", 1)
+	d.AddN("y", 4)
+	d.AddN("z", 2)
+	c.Merge(d)
+	if diff := gocmp.Diff(c.Top(5), []entry{{"y", 5, 0}, {"x", 3, 0}, {"z", 2, 0}}); diff != "" {
+		t.Errorf("Merge (-got, +want):\n%s", diff)
+	}
+}
@@ -0,0 +1,187 @@
+// Package tsbuf implements a fixed-capacity buffer of time-stamped values.
+//
+// A [Buffer] holds the most recent values of a time series in a circular
+// array, in order by time. When the buffer is full, adding a value evicts the
+// oldest one. Values can be queried by time window in logarithmic time, which
+// makes a Buffer suitable for keeping a sliding window of samples, such as
+// the recent measurements of a monitoring agent.
+//
+// For example:
+//
+//	b := tsbuf.New[float64](1000)
+//	b.Add(time.Now(), 0.5)
+//	...
+//	for e := range b.Range(start, end) {
+//	   fmt.Println(e.Time, e.Value)
+//	}
+package tsbuf
+
+import (
+	"iter"
+	"sort"
+	"time"
+)
+
+// An Entry is a value with an associated timestamp.
+type Entry[T any] struct {
+	Time  time.Time
+	Value T
+}
+
+// A Buffer is a fixed-capacity buffer of time-stamped values, ordered by
+// time. Values with equal timestamps are kept in the order they were added.
+// A zero Buffer is not ready for use; construct one with [New]. A *Buffer is
+// not safe for concurrent use without external synchronization.
+//
+// Add, Len, Oldest, and Newest take O(1) time when values arrive in order.
+// Range and TrimBefore take O(lg n) time to find the start of the window.
+type Buffer[T any] struct {
+	buf  []Entry[T] // circular; len(buf) is the capacity
+	head int        // offset of the oldest entry
+	n    int        // number of entries
+}
+
+// New constructs a new empty buffer with room for capacity values.
+// New panics if capacity ≤ 0.
+func New[T any](capacity int) *Buffer[T] {
+	if capacity <= 0 {
+		panic("tsbuf: capacity must be positive")
+	}
+	return &Buffer[T]{buf: make([]Entry[T], capacity)}
+}
+
+// Len reports the number of values in b.
+func (b *Buffer[T]) Len() int { return b.n }
+
+// Cap reports the maximum number of values b can hold.
+func (b *Buffer[T]) Cap() int { return len(b.buf) }
+
+// IsEmpty reports whether b is empty.
+func (b *Buffer[T]) IsEmpty() bool { return b.n == 0 }
+
+// IsFull reports whether b is full, so that adding a value will evict the
+// oldest value.
+func (b *Buffer[T]) IsFull() bool { return b.n == len(b.buf) }
+
+// Clear discards all the values in b, leaving it empty.
+func (b *Buffer[T]) Clear() {
+	clear(b.buf)
+	b.head, b.n = 0, 0
+}
+
+// Add adds v to b with timestamp ts, and reports whether it was added. If b is
+// full, the oldest value is evicted to make room.
+//
+// Values normally arrive in order, but if ts is earlier than the timestamp of
+// the newest value, v is placed in order by time. This costs time proportional
+// to the number of newer values. If b is full and ts is earlier than the
+// timestamp of every value in b, v would be evicted at once, so Add discards it
+// and reports false.
+func (b *Buffer[T]) Add(ts time.Time, v T) bool {
+	if b.n == len(b.buf) {
+		if ts.Before(b.at(0).Time) {
+			return false
+		}
+		b.evict(1)
+	}
+
+	// Find the position for the new entry, shifting newer entries up.
+	i := b.n
+	for ; i > 0 && b.at(i-1).Time.After(ts); i-- {
+		*b.at(i) = *b.at(i - 1)
+	}
+	*b.at(i) = Entry[T]{Time: ts, Value: v}
+	b.n++
+	return true
+}
+
+// Oldest reports whether b is non-empty, and if so returns its oldest entry.
+func (b *Buffer[T]) Oldest() (Entry[T], bool) {
+	if b.n == 0 {
+		return Entry[T]{}, false
+	}
+	return *b.at(0), true
+}
+
+// Newest reports whether b is non-empty, and if so returns its newest entry.
+func (b *Buffer[T]) Newest() (Entry[T], bool) {
+	if b.n == 0 {
+		return Entry[T]{}, false
+	}
+	return *b.at(b.n - 1), true
+}
+
+// TrimBefore discards all the values in b with timestamps earlier than ts, and
+// reports the number of values discarded.
+func (b *Buffer[T]) TrimBefore(ts time.Time) int {
+	k := b.search(ts)
+	b.evict(k)
+	return k
+}
+
+// Each is a range function that calls f with each entry of b in order by
+// time, from oldest to newest. If f returns false, Each returns immediately.
+// The buffer must not be modified while Each is running.
+func (b *Buffer[T]) Each(f func(Entry[T]) bool) {
+	for i := range b.n {
+		if !f(*b.at(i)) {
+			return
+		}
+	}
+}
+
+// Range returns an iterator over the entries of b with timestamps greater than
+// or equal to from and less than to, in order by time. If from is not before
+// to, the range is empty. The buffer must not be modified while the iterator
+// is running.
+func (b *Buffer[T]) Range(from, to time.Time) iter.Seq[Entry[T]] {
+	return func(yield func(Entry[T]) bool) {
+		for i := b.search(from); i < b.n; i++ {
+			e := b.at(i)
+			if !e.Time.Before(to) || !yield(*e) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns a slice of the values of b in order by time, from oldest to
+// newest. If b is empty, Values returns nil.
+func (b *Buffer[T]) Values() []T {
+	if b.n == 0 {
+		return nil
+	}
+	out := make([]T, b.n)
+	for i := range out {
+		out[i] = b.at(i).Value
+	}
+	return out
+}
+
+// at returns a pointer to the entry at logical offset i of b, where 0 is the
+// oldest entry. The offset may equal b.n, if b is not full.
+func (b *Buffer[T]) at(i int) *Entry[T] {
+	pos := b.head + i
+	if pos >= len(b.buf) {
+		pos -= len(b.buf)
+	}
+	return &b.buf[pos]
+}
+
+// search returns the logical offset of the first entry of b whose timestamp is
+// not earlier than ts, or b.n if there is none.
+func (b *Buffer[T]) search(ts time.Time) int {
+	return sort.Search(b.n, func(i int) bool { return !b.at(i).Time.Before(ts) })
+}
+
+// evict discards the k oldest entries of b.
+func (b *Buffer[T]) evict(k int) {
+	for i := range k {
+		*b.at(i) = Entry[T]{} // release the value for collection
+	}
+	b.head += k
+	if b.head >= len(b.buf) {
+		b.head -= len(b.buf)
+	}
+	b.n -= k
+}
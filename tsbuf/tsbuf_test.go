@@ -0,0 +1,185 @@
+package tsbuf_test
+
+import (
+	"iter"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/creachadair/mds/mtest"
+	"github.com/creachadair/mds/tsbuf"
+	gocmp "github.com/google/go-cmp/cmp"
+)
+
+var epoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// at returns the time at offset i seconds from the epoch.
+func at(i int) time.Time { return epoch.Add(time.Duration(i) * time.Second) }
+
+// values returns the values of the entries in seq.
+func values(seq iter.Seq[tsbuf.Entry[string]]) []string {
+	var out []string
+	for e := range seq {
+		out = append(out, e.Value)
+	}
+	return out
+}
+
+func TestBuffer(t *testing.T) {
+	mtest.MustPanic(t, func() { tsbuf.New[int](0) })
+
+	b := tsbuf.New[string](4)
+	if !b.IsEmpty() || b.IsFull() || b.Len() != 0 || b.Cap() != 4 {
+		t.Errorf("New: got len=%d cap=%d, want 0, 4", b.Len(), b.Cap())
+	}
+	if e, ok := b.Oldest(); ok {
+		t.Errorf("Oldest: got (%v, true), want false", e)
+	}
+	if e, ok := b.Newest(); ok {
+		t.Errorf("Newest: got (%v, true), want false", e)
+	}
+	if got := b.Values(); got != nil {
+		t.Errorf("Values: got %q, want nil", got)
+	}
+
+	check := func(want ...string) {
+		t.Helper()
+		if diff := gocmp.Diff(b.Values(), want); diff != "" {
+			t.Errorf("Values (-got, +want):\n%s", diff)
+		}
+		if diff := gocmp.Diff(values(b.Each), want); diff != "" {
+			t.Errorf("Each (-got, +want):\n%s", diff)
+		}
+		if b.Len() != len(want) {
+			t.Errorf("Len: got %d, want %d", b.Len(), len(want))
+		}
+	}
+
+	for i, v := range []string{"a", "b", "c"} {
+		if !b.Add(at(i), v) {
+			t.Errorf("Add(%q): got false, want true", v)
+		}
+	}
+	check("a", "b", "c")
+
+	// Adding to a full buffer evicts the oldest values.
+	b.Add(at(3), "d")
+	if !b.IsFull() {
+		t.Error("IsFull: got false, want true")
+	}
+	b.Add(at(4), "e")
+	b.Add(at(5), "f")
+	check("c", "d", "e", "f")
+
+	if e, ok := b.Oldest(); !ok || e.Value != "c" || !e.Time.Equal(at(2)) {
+		t.Errorf("Oldest: got (%v, %v), want c at %v", e, ok, at(2))
+	}
+	if e, ok := b.Newest(); !ok || e.Value != "f" || !e.Time.Equal(at(5)) {
+		t.Errorf("Newest: got (%v, %v), want f at %v", e, ok, at(5))
+	}
+
+	// Values out of order are placed by time.
+	b.Add(at(3), "d2") // after the existing entry at the same time
+	check("d", "d2", "e", "f")
+	b.Add(at(4).Add(-time.Millisecond), "x")
+	check("d2", "x", "e", "f")
+
+	// A value older than everything in a full buffer is discarded.
+	if b.Add(at(1), "old") {
+		t.Error("Add(old): got true, want false")
+	}
+	check("d2", "x", "e", "f")
+
+	b.Clear()
+	check()
+	b.Add(at(10), "z")
+	check("z")
+}
+
+func TestRange(t *testing.T) {
+	b := tsbuf.New[string](8)
+	for i, v := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"} {
+		b.Add(at(2*i), v) // times 0, 2, 4, ..., 18; a and b are evicted
+	}
+
+	tests := []struct {
+		from, to int
+		want     []string
+	}{
+		{0, 100, []string{"c", "d", "e", "f", "g", "h", "i", "j"}},
+		{4, 5, []string{"c"}},
+		{4, 4, nil},
+		{5, 4, nil},
+		{5, 11, []string{"d", "e", "f"}},
+		{6, 12, []string{"d", "e", "f"}},
+		{17, 18, nil},
+		{18, 19, []string{"j"}},
+		{20, 30, nil},
+		{-5, 1, nil},
+	}
+	for _, tc := range tests {
+		got := values(b.Range(at(tc.from), at(tc.to)))
+		if diff := gocmp.Diff(got, tc.want); diff != "" {
+			t.Errorf("Range(%d, %d) (-got, +want):\n%s", tc.from, tc.to, diff)
+		}
+	}
+
+	// Stopping early is honored.
+	for e := range b.Range(at(0), at(100)) {
+		if e.Value != "c" {
+			t.Errorf("Range: got %q, want c", e.Value)
+		}
+		break
+	}
+}
+
+func TestTrimBefore(t *testing.T) {
+	b := tsbuf.New[int](5)
+	for i := range 7 {
+		b.Add(at(i), i) // 2, 3, 4, 5, 6 remain
+	}
+	if n := b.TrimBefore(at(0)); n != 0 {
+		t.Errorf("TrimBefore(0): got %d, want 0", n)
+	}
+	if n := b.TrimBefore(at(4)); n != 2 {
+		t.Errorf("TrimBefore(4): got %d, want 2", n)
+	}
+	if diff := gocmp.Diff(b.Values(), []int{4, 5, 6}); diff != "" {
+		t.Errorf("Values (-got, +want):\n%s", diff)
+	}
+
+	// The buffer wraps around correctly after trimming.
+	for i := 7; i < 12; i++ {
+		b.Add(at(i), i)
+	}
+	if diff := gocmp.Diff(b.Values(), []int{7, 8, 9, 10, 11}); diff != "" {
+		t.Errorf("Values (-got, +want):\n%s", diff)
+	}
+	if n := b.TrimBefore(at(100)); n != 5 || !b.IsEmpty() {
+		t.Errorf("TrimBefore(100): got %d, len %d; want 5, 0", n, b.Len())
+	}
+}
+
+func TestOrder(t *testing.T) {
+	// Regardless of arrival order, the buffer holds the newest values in order.
+	const size = 16
+	times := []int{5, 3, 9, 1, 1, 7, 12, 30, 2, 18, 25, 4, 11, 27, 8, 6, 19, 22, 14, 0, 29, 13}
+	b := tsbuf.New[int](size)
+	for _, v := range times {
+		b.Add(at(v), v)
+	}
+	got := b.Values()
+	if !slices.IsSorted(got) {
+		t.Errorf("Values are not in order: %v", got)
+	}
+	if len(got) != size {
+		t.Errorf("Len: got %d, want %d", len(got), size)
+	}
+	// The oldest values may have been discarded on arrival or evicted, but
+	// the newest values must all be present.
+	for _, v := range []int{30, 29, 27, 25, 22, 19, 18, 14, 13, 12, 11} {
+		if !slices.Contains(got, v) {
+			t.Errorf("Missing value %d from %v", v, got)
+		}
+	}
+}
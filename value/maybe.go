@@ -65,3 +65,40 @@ func Check[T any](v T, err error) Maybe[T] {
 	}
 	return Maybe[T]{}
 }
+
+// OK returns Just(v) if ok is true; otherwise it returns Absent(). It is
+// useful to wrap the (value, ok) results returned by many functions, for
+// example:
+//
+//	m := value.OK(q.Pop())
+func OK[T any](v T, ok bool) Maybe[T] {
+	if ok {
+		return Just(v)
+	}
+	return Maybe[T]{}
+}
+
+// OrErr returns the value held in m and a nil error, if m holds a value.
+// Otherwise it returns the zero of T and err.
+func (m Maybe[T]) OrErr(err error) (T, error) {
+	if m.present {
+		return m.value, nil
+	}
+	return m.value, err
+}
+
+// Must returns v if err == nil; otherwise it panics with err.
+func Must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// MustOK returns v if ok is true; otherwise it panics.
+func MustOK[T any](v T, ok bool) T {
+	if !ok {
+		panic(fmt.Sprintf("value of type %T is not present", v))
+	}
+	return v
+}
@@ -1,9 +1,11 @@
 package value_test
 
 import (
+	"errors"
 	"strconv"
 	"testing"
 
+	"github.com/creachadair/mds/mtest"
 	"github.com/creachadair/mds/value"
 )
 
@@ -99,6 +101,39 @@ func TestCheck(t *testing.T) {
 	})
 }
 
+func TestOK(t *testing.T) {
+	m := map[string]int{"a": 1}
+	lookup := func(key string) (int, bool) { v, ok := m[key]; return v, ok }
+
+	if got, want := value.OK(lookup("a")), value.Just(1); got != want {
+		t.Errorf("OK(a): got %v, want %v", got, want)
+	}
+	if got := value.OK(lookup("b")); got.Present() {
+		t.Errorf("OK(b): got %v, want absent", got)
+	}
+
+	if got := value.MustOK(lookup("a")); got != 1 {
+		t.Errorf("MustOK(a): got %d, want 1", got)
+	}
+	mtest.MustPanic(t, func() { value.MustOK(lookup("b")) })
+
+	if got := value.Must(strconv.Atoi("5")); got != 5 {
+		t.Errorf("Must(5): got %d, want 5", got)
+	}
+	mtest.MustPanic(t, func() { value.Must(strconv.Atoi("bogus")) })
+}
+
+func TestOrErr(t *testing.T) {
+	errMissing := errors.New("missing")
+
+	if v, err := value.Just("x").OrErr(errMissing); v != "x" || err != nil {
+		t.Errorf("Just(x).OrErr: got (%q, %v), want (x, nil)", v, err)
+	}
+	if v, err := value.Absent[string]().OrErr(errMissing); v != "" || err != errMissing {
+		t.Errorf("Absent.OrErr: got (%q, %v), want (\"\", %v)", v, err, errMissing)
+	}
+}
+
 func TestAtMaybe(t *testing.T) {
 	tests := []struct {
 		input *string